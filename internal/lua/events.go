@@ -72,6 +72,36 @@ func RegisterEvents(L *lua.LState) {
 	registerHandler(hll.EVENT_OBJECTIVE_CAPPED, "onObjectiveCapped")
 	registerHandler(hll.EVENT_POSITION_CHANGED, "onPositionChanged")
 	registerHandler(hll.EVENT_CLAN_TAG_CHANGED, "onClanTagChanged")
+	registerHandler(hll.EVENT_PLAYER_SPAWNED, "onPlayerSpawned")
+	registerHandler(hll.EVENT_PLAYER_DESPAWNED, "onPlayerDespawned")
+	registerHandler(hll.EVENT_VEHICLE_DESTROYED, "onVehicleDestroyed")
+	registerHandler(hll.EVENT_VEHICLE_KILL, "onVehicleKill")
+	registerHandler(hll.EVENT_LEVEL_UP, "onLevelUp")
+	registerHandler(hll.EVENT_KILL_STREAK, "onKillStreak")
+	registerHandler(hll.EVENT_KILL_STREAK_ENDED, "onKillStreakEnded")
+	registerHandler(hll.EVENT_MULTI_KILL, "onMultiKill")
+	registerHandler(hll.EVENT_MATCH_PHASE_CHANGED, "onMatchPhaseChanged")
+	registerHandler(hll.EVENT_SECTOR_CAPTURED, "onSectorCaptured")
+	registerHandler(hll.EVENT_SERVER_FULL, "onServerFull")
+	registerHandler(hll.EVENT_SERVER_NOT_FULL, "onServerNotFull")
+	registerHandler(hll.EVENT_QUEUE_CHANGED, "onQueueChanged")
+	registerHandler(hll.EVENT_VIP_QUEUE_CHANGED, "onVIPQueueChanged")
+	registerHandler(hll.EVENT_POPULATION_CROSSED, "onPopulationThresholdCrossed")
+	registerHandler(hll.EVENT_SQUAD_CREATED, "onSquadCreated")
+	registerHandler(hll.EVENT_SQUAD_DISBANDED, "onSquadDisbanded")
+	registerHandler(hll.EVENT_SQUAD_LEADER_CHANGED, "onSquadLeaderChanged")
+	registerHandler(hll.EVENT_SQUAD_LEADERLESS, "onSquadLeaderless")
+	registerHandler(hll.EVENT_COMMANDER_TOOK_ROLE, "onCommanderTookRole")
+	registerHandler(hll.EVENT_COMMANDER_LEFT_ROLE, "onCommanderLeftRole")
+	registerHandler(hll.EVENT_SERVER_SNAPSHOT, "onServerSnapshot")
+	registerHandler(hll.EVENT_SESSION_CLOSED, "onSessionClosed")
+	registerHandler(hll.EVENT_STRONGPOINT_ENTERED, "onStrongpointEntered")
+	registerHandler(hll.EVENT_STRONGPOINT_LEFT, "onStrongpointLeft")
+	registerHandler(hll.EVENT_ENEMY_HQ_ENTERED, "onEnemyHQEntered")
+	registerHandler(hll.EVENT_ENEMY_PROXIMITY, "onEnemyProximity")
+	registerHandler(hll.EVENT_CREW_FORMED, "onCrewFormed")
+	registerHandler(hll.EVENT_CREW_DISSOLVED, "onCrewDissolved")
+	registerHandler(hll.EVENT_SUSPICIOUS_KILL, "onSuspiciousKill")
 }
 
 func UnregisterEvents() {
@@ -14,6 +14,9 @@ var callbackPrefix = "On"
 func rconFunctions() []string {
 	excludedFuncs := []string{
 		"Close",
+		"ReplayJournal",
+		"AddSink",
+		"SpatialIndex",
 	}
 
 	funcs := []string{}
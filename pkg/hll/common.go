@@ -3,8 +3,8 @@ package hll
 import "time"
 
 type TeamData struct {
-	Allies int
-	Axis   int
+	Allies int `json:"allies"`
+	Axis   int `json:"axis"`
 }
 
 type GameState struct {
@@ -69,23 +69,63 @@ type ServerConfig struct {
 }
 
 type SessionInfo struct {
-	ServerName         string
-	MapName            string
-	GameMode           GameModeIdentifier
-	RemainingMatchTime time.Duration
-	MatchTime          time.Duration
-	AlliedFaction      FactionIdentifier
-	AxisFaction        FactionIdentifier
-	MaxPlayerCount     int
-	AlliedScore        int
-	AxisScore          int
-	PlayerCount        int
-	AlliedPlayerCount  int
-	AxisPlayerCount    int
-	MaxQueueCount      int
-	QueueCount         int
-	MaxVIPQueueCount   int
-	VIPQueueCount      int
+	ServerName         string             `json:"serverName"`
+	MapName            string             `json:"mapName"`
+	MapID              string             `json:"mapID"`
+	GameMode           GameModeIdentifier `json:"gameMode"`
+	RemainingMatchTime time.Duration      `json:"remainingMatchTime"`
+	MatchTime          time.Duration      `json:"matchTime"`
+	AlliedFaction      FactionIdentifier  `json:"alliedFaction"`
+	AxisFaction        FactionIdentifier  `json:"axisFaction"`
+	MaxPlayerCount     int                `json:"maxPlayerCount"`
+	AlliedScore        int                `json:"alliedScore"`
+	AxisScore          int                `json:"axisScore"`
+	PlayerCount        int                `json:"playerCount"`
+	AlliedPlayerCount  int                `json:"alliedPlayerCount"`
+	AxisPlayerCount    int                `json:"axisPlayerCount"`
+	MaxQueueCount      int                `json:"maxQueueCount"`
+	QueueCount         int                `json:"queueCount"`
+	MaxVIPQueueCount   int                `json:"maxVIPQueueCount"`
+	VIPQueueCount      int                `json:"vipQueueCount"`
+}
+
+type MatchPhase string
+
+const (
+	MATCH_PHASE_UNKNOWN     MatchPhase = "Unknown"
+	MATCH_PHASE_WARMUP      MatchPhase = "Warmup"
+	MATCH_PHASE_IN_PROGRESS MatchPhase = "In Progress"
+	MATCH_PHASE_ENDED       MatchPhase = "Ended"
+)
+
+type Match struct {
+	Layer     Layer      `json:"layer"`
+	Phase     MatchPhase `json:"phase"`
+	StartTime time.Time  `json:"startTime"` // estimated if the match was already running when the events got enabled
+	EndTime   time.Time  `json:"endTime"`
+	Score     TeamData   `json:"score"` // final score, only set once the match ended
+}
+
+// the time a player spent on the server between joining and leaving
+type PlayerSession struct {
+	Player   PlayerInfo                       `json:"player"`
+	Start    time.Time                        `json:"start"`
+	End      time.Time                        `json:"end"`      // zero while the session is running
+	Inferred bool                             `json:"inferred"` // the disconnect was missed, the end is the last time the player was seen
+	TeamTime map[TeamIdentifier]time.Duration `json:"teamTime"` // only covers the time the player was seen in the player list
+	RoleTime map[RoleIdentifier]time.Duration `json:"roleTime"` // only covers the time the player was seen in the player list
+}
+
+func (ps PlayerSession) Active() bool {
+	return ps.End.IsZero()
+}
+
+// the duration of a running session is measured up to now
+func (ps PlayerSession) Duration() time.Duration {
+	if ps.Active() {
+		return time.Since(ps.Start)
+	}
+	return ps.End.Sub(ps.Start)
 }
 
 type LogEntry struct {
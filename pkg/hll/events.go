@@ -1,7 +1,11 @@
 package hll
 
 import (
+	"fmt"
+	"reflect"
 	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/logger"
 )
 
 type EventType string
@@ -25,31 +29,73 @@ const (
 
 	// native log events above; custom events below
 
-	EVENT_ENTER_ADMINCAM      EventType = "ADMINCAM ENTERED"
-	EVENT_LEAVE_ADMINCAM      EventType = "ADMINCAM LEFT"
-	EVENT_VOTE_KICK_STARTED   EventType = "VOTE KICK STARTED"
-	EVENT_VOTE_SUBMITTED      EventType = "VOTE SUBMITTED"
-	EVENT_VOTE_KICK_COMPLETED EventType = "VOTE KICK COMPLETED"
-	EVENT_TEAM_SWITCHED       EventType = "TEAM SWITCHED"
-	EVENT_SQUAD_SWITCHED      EventType = "SQUAD SWITCHED"
-	EVENT_SCORE_UPDATE        EventType = "SCORE UPDATE"
-	EVENT_ROLE_CHANGED        EventType = "ROLE CHANGED"
-	EVENT_LOADOUT_CHANGED     EventType = "LOADOUT CHANGED"
-	EVENT_OBJECTIVE_CAPPED    EventType = "OBJECTIVE CAPPED"
-	EVENT_POSITION_CHANGED    EventType = "POSITION CHANGED"
-	EVENT_CLAN_TAG_CHANGED    EventType = "CLAN TAG CHANGED"
-	EVENT_GENERIC             EventType = "GENERIC"
+	EVENT_ENTER_ADMINCAM       EventType = "ADMINCAM ENTERED"
+	EVENT_LEAVE_ADMINCAM       EventType = "ADMINCAM LEFT"
+	EVENT_VOTE_KICK_STARTED    EventType = "VOTE KICK STARTED"
+	EVENT_VOTE_SUBMITTED       EventType = "VOTE SUBMITTED"
+	EVENT_VOTE_KICK_COMPLETED  EventType = "VOTE KICK COMPLETED"
+	EVENT_TEAM_SWITCHED        EventType = "TEAM SWITCHED"
+	EVENT_SQUAD_SWITCHED       EventType = "SQUAD SWITCHED"
+	EVENT_SCORE_UPDATE         EventType = "SCORE UPDATE"
+	EVENT_ROLE_CHANGED         EventType = "ROLE CHANGED"
+	EVENT_LOADOUT_CHANGED      EventType = "LOADOUT CHANGED"
+	EVENT_OBJECTIVE_CAPPED     EventType = "OBJECTIVE CAPPED"
+	EVENT_POSITION_CHANGED     EventType = "POSITION CHANGED"
+	EVENT_CLAN_TAG_CHANGED     EventType = "CLAN TAG CHANGED"
+	EVENT_PLAYER_SPAWNED       EventType = "PLAYER SPAWNED"
+	EVENT_PLAYER_DESPAWNED     EventType = "PLAYER DESPAWNED"
+	EVENT_VEHICLE_DESTROYED    EventType = "VEHICLE DESTROYED"
+	EVENT_VEHICLE_KILL         EventType = "VEHICLE KILL"
+	EVENT_LEVEL_UP             EventType = "LEVEL UP"
+	EVENT_KILL_STREAK          EventType = "KILL STREAK"
+	EVENT_KILL_STREAK_ENDED    EventType = "KILL STREAK ENDED"
+	EVENT_MULTI_KILL           EventType = "MULTI KILL"
+	EVENT_MATCH_PHASE_CHANGED  EventType = "MATCH PHASE CHANGED"
+	EVENT_SECTOR_CAPTURED      EventType = "SECTOR CAPTURED"
+	EVENT_SERVER_FULL          EventType = "SERVER FULL"
+	EVENT_SERVER_NOT_FULL      EventType = "SERVER NOT FULL"
+	EVENT_QUEUE_CHANGED        EventType = "QUEUE CHANGED"
+	EVENT_VIP_QUEUE_CHANGED    EventType = "VIP QUEUE CHANGED"
+	EVENT_POPULATION_CROSSED   EventType = "POPULATION THRESHOLD CROSSED"
+	EVENT_SQUAD_CREATED        EventType = "SQUAD CREATED"
+	EVENT_SQUAD_DISBANDED      EventType = "SQUAD DISBANDED"
+	EVENT_SQUAD_LEADER_CHANGED EventType = "SQUAD LEADER CHANGED"
+	EVENT_SQUAD_LEADERLESS     EventType = "SQUAD LEADERLESS"
+	EVENT_COMMANDER_TOOK_ROLE  EventType = "COMMANDER TOOK ROLE"
+	EVENT_COMMANDER_LEFT_ROLE  EventType = "COMMANDER LEFT ROLE"
+	EVENT_SERVER_SNAPSHOT      EventType = "SERVER SNAPSHOT"
+	EVENT_SESSION_CLOSED       EventType = "SESSION CLOSED"
+	EVENT_STRONGPOINT_ENTERED  EventType = "STRONGPOINT ENTERED"
+	EVENT_STRONGPOINT_LEFT     EventType = "STRONGPOINT LEFT"
+	EVENT_ENEMY_HQ_ENTERED     EventType = "ENEMY HQ ENTERED"
+	EVENT_ENEMY_PROXIMITY      EventType = "ENEMY PROXIMITY"
+	EVENT_CREW_FORMED          EventType = "CREW FORMED"
+	EVENT_CREW_DISSOLVED       EventType = "CREW DISSOLVED"
+	EVENT_SUSPICIOUS_KILL      EventType = "SUSPICIOUS KILL"
+	EVENT_GENERIC              EventType = "GENERIC"
+)
+
+type EventSource string
+
+const (
+	EVENT_SOURCE_UNKNOWN EventSource = ""
+	EVENT_SOURCE_LOG     EventSource = "Log"  // parsed from the server logs, the time has a resolution of one second
+	EVENT_SOURCE_POLL    EventSource = "Poll" // derived from polling the server state, the time is taken on the client
 )
 
 type Event interface {
 	Type() EventType
 	Time() time.Time
+	Sequence() uint64
+	Source() EventSource
 	AffectedPlayers() []PlayerInfo
 }
 
 type GenericEvent struct {
-	EventType EventType
-	EventTime time.Time
+	EventType     EventType   `json:"eventType"`
+	EventTime     time.Time   `json:"eventTime"`
+	EventSequence uint64      `json:"eventSequence"` // monotonic number assigned when the event is delivered, 0 if not yet delivered
+	EventSource   EventSource `json:"eventSource"`
 }
 
 func (ge GenericEvent) Type() EventType {
@@ -60,13 +106,58 @@ func (ge GenericEvent) Time() time.Time {
 	return ge.EventTime
 }
 
+func (ge GenericEvent) Sequence() uint64 {
+	return ge.EventSequence
+}
+
+func (ge GenericEvent) Source() EventSource {
+	return ge.EventSource
+}
+
 func (ge GenericEvent) AffectedPlayers() []PlayerInfo {
 	return []PlayerInfo{}
 }
 
+// returns a copy of the event with the sequence number set;
+// the event has to be a struct value embedding GenericEvent, other events are returned unchanged
+func WithSequence(event Event, sequence uint64) Event {
+	return updateGenericEvent(event, func(ge *GenericEvent) {
+		ge.EventSequence = sequence
+	})
+}
+
+// returns a copy of the event with the source set, same requirements as WithSequence
+func WithSource(event Event, source EventSource) Event {
+	return updateGenericEvent(event, func(ge *GenericEvent) {
+		ge.EventSource = source
+	})
+}
+
+func updateGenericEvent(event Event, update func(*GenericEvent)) Event {
+	if ge, ok := event.(GenericEvent); ok {
+		update(&ge)
+		return ge
+	}
+
+	value := reflect.ValueOf(event)
+	if value.Kind() != reflect.Struct {
+		logger.Warn("event", fmt.Sprintf("%T", event), "is no struct value, its metadata is not set")
+		return event
+	}
+	eventCopy := reflect.New(value.Type()).Elem()
+	eventCopy.Set(value)
+	field := eventCopy.FieldByName("GenericEvent")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(GenericEvent{}) {
+		logger.Warn("event", fmt.Sprintf("%T", event), "does not embed GenericEvent, its metadata is not set")
+		return event
+	}
+	update(field.Addr().Interface().(*GenericEvent))
+	return eventCopy.Interface().(Event)
+}
+
 type ConnectEvent struct {
 	GenericEvent
-	Player PlayerInfo
+	Player PlayerInfo `json:"player"`
 }
 
 func (ce ConnectEvent) AffectedPlayers() []PlayerInfo {
@@ -75,18 +166,43 @@ func (ce ConnectEvent) AffectedPlayers() []PlayerInfo {
 
 type DisconnectEvent struct {
 	GenericEvent
-	Player PlayerInfo
+	Player PlayerInfo `json:"player"`
 }
 
 func (de DisconnectEvent) AffectedPlayers() []PlayerInfo {
 	return []PlayerInfo{de.Player}
 }
 
+// KillContext holds the state of killer and victim at the time of a kill,
+// taken from the latest serverinfo snapshot
+type KillContext struct {
+	Killer             DetailedPlayerInfo `json:"killer"`
+	Victim             DetailedPlayerInfo `json:"victim"`
+	Distance           float64            `json:"distance"`           // in meters; 0 if the position of either player is unknown
+	KillerPositionLive bool               `json:"killerPositionLive"` // false if the position is the last known one of an earlier snapshot
+	VictimPositionLive bool               `json:"victimPositionLive"` // false if the position is the last known one of an earlier snapshot
+}
+
+// active positions are taken as live, the caller has to unset the flags for positions of earlier snapshots
+func NewKillContext(killer DetailedPlayerInfo, victim DetailedPlayerInfo) *KillContext {
+	kc := KillContext{
+		Killer:             killer,
+		Victim:             victim,
+		KillerPositionLive: killer.Position.IsActive(),
+		VictimPositionLive: victim.Position.IsActive(),
+	}
+	if killer.Position.IsActive() && victim.Position.IsActive() {
+		kc.Distance = float64(killer.SpacialDistanceTo(victim.Position)) / 100
+	}
+	return &kc
+}
+
 type KillEvent struct {
 	GenericEvent
-	Killer PlayerInfo
-	Victim PlayerInfo
-	Weapon Weapon
+	Killer  PlayerInfo   `json:"killer"`
+	Victim  PlayerInfo   `json:"victim"`
+	Weapon  Weapon       `json:"weapon"`
+	Context *KillContext `json:"context"`
 }
 
 func (ke KillEvent) AffectedPlayers() []PlayerInfo {
@@ -95,9 +211,10 @@ func (ke KillEvent) AffectedPlayers() []PlayerInfo {
 
 type DeathEvent struct {
 	GenericEvent
-	Victim PlayerInfo
-	Killer PlayerInfo
-	Weapon Weapon
+	Victim  PlayerInfo   `json:"victim"`
+	Killer  PlayerInfo   `json:"killer"`
+	Weapon  Weapon       `json:"weapon"`
+	Context *KillContext `json:"context"`
 }
 
 func (de DeathEvent) AffectedPlayers() []PlayerInfo {
@@ -106,9 +223,10 @@ func (de DeathEvent) AffectedPlayers() []PlayerInfo {
 
 type TeamKillEvent struct {
 	GenericEvent
-	Killer PlayerInfo
-	Victim PlayerInfo
-	Weapon Weapon
+	Killer  PlayerInfo   `json:"killer"`
+	Victim  PlayerInfo   `json:"victim"`
+	Weapon  Weapon       `json:"weapon"`
+	Context *KillContext `json:"context"`
 }
 
 func (tke TeamKillEvent) AffectedPlayers() []PlayerInfo {
@@ -117,9 +235,10 @@ func (tke TeamKillEvent) AffectedPlayers() []PlayerInfo {
 
 type TeamDeathEvent struct {
 	GenericEvent
-	Victim PlayerInfo
-	Killer PlayerInfo
-	Weapon Weapon
+	Victim  PlayerInfo   `json:"victim"`
+	Killer  PlayerInfo   `json:"killer"`
+	Weapon  Weapon       `json:"weapon"`
+	Context *KillContext `json:"context"`
 }
 
 func (tde TeamDeathEvent) AffectedPlayers() []PlayerInfo {
@@ -128,9 +247,9 @@ func (tde TeamDeathEvent) AffectedPlayers() []PlayerInfo {
 
 type TeamSwitchEvent struct {
 	GenericEvent
-	Player PlayerInfo
-	From   TeamIdentifier
-	To     TeamIdentifier
+	Player PlayerInfo     `json:"player"`
+	From   TeamIdentifier `json:"from"`
+	To     TeamIdentifier `json:"to"`
 }
 
 func (tse TeamSwitchEvent) AffectedPlayers() []PlayerInfo {
@@ -139,10 +258,10 @@ func (tse TeamSwitchEvent) AffectedPlayers() []PlayerInfo {
 
 type ChatEvent struct {
 	GenericEvent
-	Player  PlayerInfo
-	Team    TeamIdentifier
-	Scope   ChatScope
-	Message string
+	Player  PlayerInfo     `json:"player"`
+	Team    TeamIdentifier `json:"team"`
+	Scope   ChatScope      `json:"scope"`
+	Message string         `json:"message"`
 }
 
 func (ce ChatEvent) AffectedPlayers() []PlayerInfo {
@@ -151,8 +270,8 @@ func (ce ChatEvent) AffectedPlayers() []PlayerInfo {
 
 type BanEvent struct {
 	GenericEvent
-	Player PlayerInfo
-	Reason string
+	Player PlayerInfo `json:"player"`
+	Reason string     `json:"reason"`
 }
 
 func (be BanEvent) AffectedPlayers() []PlayerInfo {
@@ -161,8 +280,8 @@ func (be BanEvent) AffectedPlayers() []PlayerInfo {
 
 type KickEvent struct {
 	GenericEvent
-	Player PlayerInfo
-	Reason string
+	Player PlayerInfo `json:"player"`
+	Reason string     `json:"reason"`
 }
 
 func (ke KickEvent) AffectedPlayers() []PlayerInfo {
@@ -171,8 +290,8 @@ func (ke KickEvent) AffectedPlayers() []PlayerInfo {
 
 type MessageEvent struct {
 	GenericEvent
-	Player  PlayerInfo
-	Message string
+	Player  PlayerInfo `json:"player"`
+	Message string     `json:"message"`
 }
 
 func (me MessageEvent) AffectedPlayers() []PlayerInfo {
@@ -181,7 +300,7 @@ func (me MessageEvent) AffectedPlayers() []PlayerInfo {
 
 type MatchStartEvent struct {
 	GenericEvent
-	Map Map
+	Map Map `json:"map"`
 }
 
 func (mse MatchStartEvent) AffectedPlayers() []PlayerInfo {
@@ -190,8 +309,8 @@ func (mse MatchStartEvent) AffectedPlayers() []PlayerInfo {
 
 type MatchEndEvent struct {
 	GenericEvent
-	Map   Map
-	Score TeamData
+	Map   Map      `json:"map"`
+	Score TeamData `json:"score"`
 }
 
 func (mee MatchEndEvent) AffectedPlayers() []PlayerInfo {
@@ -200,7 +319,7 @@ func (mee MatchEndEvent) AffectedPlayers() []PlayerInfo {
 
 type AdminCamEnteredEvent struct {
 	GenericEvent
-	Player PlayerInfo
+	Player PlayerInfo `json:"player"`
 }
 
 func (acee AdminCamEnteredEvent) AffectedPlayers() []PlayerInfo {
@@ -209,7 +328,7 @@ func (acee AdminCamEnteredEvent) AffectedPlayers() []PlayerInfo {
 
 type AdminCamLeftEvent struct {
 	GenericEvent
-	Player PlayerInfo
+	Player PlayerInfo `json:"player"`
 }
 
 func (acle AdminCamLeftEvent) AffectedPlayers() []PlayerInfo {
@@ -218,10 +337,10 @@ func (acle AdminCamLeftEvent) AffectedPlayers() []PlayerInfo {
 
 type VoteStartedEvent struct {
 	GenericEvent
-	Reason    string
-	ID        int
-	Initiator PlayerInfo
-	Target    PlayerInfo
+	Reason    string     `json:"reason"`
+	ID        int        `json:"id"`
+	Initiator PlayerInfo `json:"initiator"`
+	Target    PlayerInfo `json:"target"`
 }
 
 func (vse VoteStartedEvent) AffectedPlayers() []PlayerInfo {
@@ -230,9 +349,9 @@ func (vse VoteStartedEvent) AffectedPlayers() []PlayerInfo {
 
 type VoteSubmittedEvent struct {
 	GenericEvent
-	Submitter PlayerInfo
-	ID        int
-	Vote      string
+	Submitter PlayerInfo `json:"submitter"`
+	ID        int        `json:"id"`
+	Vote      string     `json:"vote"`
 }
 
 func (vse VoteSubmittedEvent) AffectedPlayers() []PlayerInfo {
@@ -241,11 +360,11 @@ func (vse VoteSubmittedEvent) AffectedPlayers() []PlayerInfo {
 
 type VoteCompletedEvent struct {
 	GenericEvent
-	Reason    string
-	Result    string
-	ID        int
-	Initiator PlayerInfo
-	Target    PlayerInfo
+	Reason    string     `json:"reason"`
+	Result    string     `json:"result"`
+	ID        int        `json:"id"`
+	Initiator PlayerInfo `json:"initiator"`
+	Target    PlayerInfo `json:"target"`
 }
 
 func (vce VoteCompletedEvent) AffectedPlayers() []PlayerInfo {
@@ -254,8 +373,8 @@ func (vce VoteCompletedEvent) AffectedPlayers() []PlayerInfo {
 
 type ObjectiveCaptureEvent struct {
 	GenericEvent
-	OldScore TeamData
-	NewScore TeamData
+	OldScore TeamData `json:"oldScore"`
+	NewScore TeamData `json:"newScore"`
 }
 
 func (oce ObjectiveCaptureEvent) AffectedPlayers() []PlayerInfo {
@@ -264,9 +383,9 @@ func (oce ObjectiveCaptureEvent) AffectedPlayers() []PlayerInfo {
 
 type PlayerScoreUpdateEvent struct {
 	GenericEvent
-	Player   PlayerInfo
-	OldScore Score
-	NewScore Score
+	Player   PlayerInfo `json:"player"`
+	OldScore Score      `json:"oldScore"`
+	NewScore Score      `json:"newScore"`
 }
 
 func (psue PlayerScoreUpdateEvent) AffectedPlayers() []PlayerInfo {
@@ -275,9 +394,9 @@ func (psue PlayerScoreUpdateEvent) AffectedPlayers() []PlayerInfo {
 
 type PlayerSwitchTeamEvent struct {
 	GenericEvent
-	Player  PlayerInfo
-	OldTeam TeamIdentifier
-	NewTeam TeamIdentifier
+	Player  PlayerInfo     `json:"player"`
+	OldTeam TeamIdentifier `json:"oldTeam"`
+	NewTeam TeamIdentifier `json:"newTeam"`
 }
 
 func (pstee PlayerSwitchTeamEvent) AffectedPlayers() []PlayerInfo {
@@ -286,9 +405,9 @@ func (pstee PlayerSwitchTeamEvent) AffectedPlayers() []PlayerInfo {
 
 type PlayerSwitchSquadEvent struct {
 	GenericEvent
-	Player   PlayerInfo
-	OldSquad Unit
-	NewSquad Unit
+	Player   PlayerInfo `json:"player"`
+	OldSquad Unit       `json:"oldSquad"`
+	NewSquad Unit       `json:"newSquad"`
 }
 
 func (psse PlayerSwitchSquadEvent) AffectedPlayers() []PlayerInfo {
@@ -297,9 +416,9 @@ func (psse PlayerSwitchSquadEvent) AffectedPlayers() []PlayerInfo {
 
 type PlayerChangeRoleEvent struct {
 	GenericEvent
-	Player  PlayerInfo
-	OldRole RoleIdentifier
-	NewRole RoleIdentifier
+	Player  PlayerInfo     `json:"player"`
+	OldRole RoleIdentifier `json:"oldRole"`
+	NewRole RoleIdentifier `json:"newRole"`
 }
 
 func (pcre PlayerChangeRoleEvent) AffectedPlayers() []PlayerInfo {
@@ -308,9 +427,9 @@ func (pcre PlayerChangeRoleEvent) AffectedPlayers() []PlayerInfo {
 
 type PlayerChangeLoadoutEvent struct {
 	GenericEvent
-	Player     PlayerInfo
-	OldLoadout string
-	NewLoadout string
+	Player     PlayerInfo `json:"player"`
+	OldLoadout string     `json:"oldLoadout"`
+	NewLoadout string     `json:"newLoadout"`
 }
 
 func (pcle PlayerChangeLoadoutEvent) AffectedPlayers() []PlayerInfo {
@@ -319,9 +438,9 @@ func (pcle PlayerChangeLoadoutEvent) AffectedPlayers() []PlayerInfo {
 
 type PlayerPositionChangedEvent struct {
 	GenericEvent
-	Player PlayerInfo
-	OldPos Position
-	NewPos Position
+	Player PlayerInfo `json:"player"`
+	OldPos Position   `json:"oldPos"`
+	NewPos Position   `json:"newPos"`
 }
 
 func (ppce PlayerPositionChangedEvent) AffectedPlayers() []PlayerInfo {
@@ -330,11 +449,401 @@ func (ppce PlayerPositionChangedEvent) AffectedPlayers() []PlayerInfo {
 
 type PlayerClanTagChangedEvent struct {
 	GenericEvent
-	Player     PlayerInfo
-	OldClanTag string
-	NewClanTag string
+	Player     PlayerInfo `json:"player"`
+	OldClanTag string     `json:"oldClanTag"`
+	NewClanTag string     `json:"newClanTag"`
 }
 
 func (pctce PlayerClanTagChangedEvent) AffectedPlayers() []PlayerInfo {
 	return []PlayerInfo{pctce.Player}
 }
+
+type PlayerSpawnedEvent struct {
+	GenericEvent
+	Player        PlayerInfo   `json:"player"`
+	Position      Position     `json:"position"`
+	GridReference string       `json:"gridReference"`
+	Strongpoint   *Strongpoint `json:"strongpoint"` // nearest strongpoint of the current layer, if known
+}
+
+func (pse PlayerSpawnedEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{pse.Player}
+}
+
+type DespawnReason string
+
+const (
+	DESPAWN_REASON_DEATH    DespawnReason = "Death"
+	DESPAWN_REASON_REDEPLOY DespawnReason = "Redeploy"
+)
+
+type PlayerDespawnedEvent struct {
+	GenericEvent
+	Player   PlayerInfo    `json:"player"`
+	Position Position      `json:"position"` // last position before the despawn
+	Reason   DespawnReason `json:"reason"`
+	Killer   PlayerInfo    `json:"killer"` // only set if the despawn could be tied to a logged death
+}
+
+func (pde PlayerDespawnedEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{pde.Player}
+}
+
+type PlayerVehicleDestroyedEvent struct {
+	GenericEvent
+	Player   PlayerInfo `json:"player"`
+	OldCount int        `json:"oldCount"`
+	NewCount int        `json:"newCount"`
+}
+
+func (pvde PlayerVehicleDestroyedEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{pvde.Player}
+}
+
+type PlayerVehicleKillEvent struct {
+	GenericEvent
+	Player   PlayerInfo `json:"player"`
+	OldCount int        `json:"oldCount"`
+	NewCount int        `json:"newCount"`
+}
+
+func (pvke PlayerVehicleKillEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{pvke.Player}
+}
+
+type PlayerLevelUpEvent struct {
+	GenericEvent
+	Player   PlayerInfo `json:"player"`
+	OldLevel int        `json:"oldLevel"`
+	NewLevel int        `json:"newLevel"`
+}
+
+func (plue PlayerLevelUpEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{plue.Player}
+}
+
+type KillStreakEvent struct {
+	GenericEvent
+	Player PlayerInfo `json:"player"`
+	Streak int        `json:"streak"`
+}
+
+func (kse KillStreakEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{kse.Player}
+}
+
+type KillStreakEndedEvent struct {
+	GenericEvent
+	Player  PlayerInfo `json:"player"`
+	Streak  int        `json:"streak"`
+	EndedBy PlayerInfo `json:"endedBy"`
+}
+
+func (ksee KillStreakEndedEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{ksee.Player, ksee.EndedBy}
+}
+
+type MultiKillEvent struct {
+	GenericEvent
+	Player  PlayerInfo    `json:"player"`
+	Kills   int           `json:"kills"`
+	Window  time.Duration `json:"window"`
+	Victims []PlayerInfo  `json:"victims"`
+}
+
+func (mke MultiKillEvent) AffectedPlayers() []PlayerInfo {
+	return append([]PlayerInfo{mke.Player}, mke.Victims...)
+}
+
+type MatchPhaseChangedEvent struct {
+	GenericEvent
+	Match    Match      `json:"match"`
+	OldPhase MatchPhase `json:"oldPhase"`
+	NewPhase MatchPhase `json:"newPhase"`
+}
+
+func (mpce MatchPhaseChangedEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{}
+}
+
+type SectorCapturedEvent struct {
+	GenericEvent
+	Team        TeamIdentifier `json:"team"`
+	SectorIndex int            `json:"sectorIndex"` // index in Layer.OrderedSectors
+	Sector      Sector         `json:"sector"`
+	Strongpoint *Strongpoint   `json:"strongpoint"` // nil if the active strongpoint of the sector is unknown
+	OldScore    TeamData       `json:"oldScore"`
+	NewScore    TeamData       `json:"newScore"`
+}
+
+func (sce SectorCapturedEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{}
+}
+
+func (sce SectorCapturedEvent) String() string {
+	name := fmt.Sprintf("sector %d", sce.SectorIndex+1)
+	if sce.Strongpoint != nil {
+		name = sce.Strongpoint.Name
+	}
+	return fmt.Sprintf("%s captured %s (%d-%d)", sce.Team, name, sce.NewScore.Allies, sce.NewScore.Axis)
+}
+
+type ServerFullEvent struct {
+	GenericEvent
+	PlayerCount    int `json:"playerCount"`
+	MaxPlayerCount int `json:"maxPlayerCount"`
+	QueueCount     int `json:"queueCount"`
+}
+
+func (sfe ServerFullEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{}
+}
+
+type ServerNotFullEvent struct {
+	GenericEvent
+	PlayerCount    int `json:"playerCount"`
+	MaxPlayerCount int `json:"maxPlayerCount"`
+}
+
+func (snfe ServerNotFullEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{}
+}
+
+type QueueChangedEvent struct {
+	GenericEvent
+	OldCount int `json:"oldCount"`
+	NewCount int `json:"newCount"`
+	MaxCount int `json:"maxCount"`
+}
+
+func (qce QueueChangedEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{}
+}
+
+type VIPQueueChangedEvent struct {
+	GenericEvent
+	OldCount int `json:"oldCount"`
+	NewCount int `json:"newCount"`
+	MaxCount int `json:"maxCount"`
+}
+
+func (vqce VIPQueueChangedEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{}
+}
+
+type PopulationThresholdCrossedEvent struct {
+	GenericEvent
+	Threshold int  `json:"threshold"`
+	OldCount  int  `json:"oldCount"`
+	NewCount  int  `json:"newCount"`
+	Rising    bool `json:"rising"` // true if the population grew past the threshold, false if it dropped below it
+}
+
+func (ptce PopulationThresholdCrossedEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{}
+}
+
+func squadPlayers(squad SquadView) []PlayerInfo {
+	players := []PlayerInfo{}
+	for _, player := range squad.Players {
+		players = append(players, player.PlayerInfo)
+	}
+	return players
+}
+
+type SquadCreatedEvent struct {
+	GenericEvent
+	Squad SquadView `json:"squad"`
+}
+
+func (sce SquadCreatedEvent) AffectedPlayers() []PlayerInfo {
+	return squadPlayers(sce.Squad)
+}
+
+type SquadDisbandedEvent struct {
+	GenericEvent
+	Squad SquadView `json:"squad"` // the last known state of the squad
+}
+
+func (sde SquadDisbandedEvent) AffectedPlayers() []PlayerInfo {
+	return squadPlayers(sde.Squad)
+}
+
+type SquadLeaderChangedEvent struct {
+	GenericEvent
+	Squad     SquadView  `json:"squad"`
+	OldLeader PlayerInfo `json:"oldLeader"` // empty if the squad had no leader before
+	NewLeader PlayerInfo `json:"newLeader"`
+}
+
+func (slce SquadLeaderChangedEvent) AffectedPlayers() []PlayerInfo {
+	if slce.OldLeader.ID == "" {
+		return []PlayerInfo{slce.NewLeader}
+	}
+	return []PlayerInfo{slce.OldLeader, slce.NewLeader}
+}
+
+type SquadLeaderlessEvent struct {
+	GenericEvent
+	Squad     SquadView  `json:"squad"`
+	OldLeader PlayerInfo `json:"oldLeader"`
+}
+
+func (sle SquadLeaderlessEvent) AffectedPlayers() []PlayerInfo {
+	return squadPlayers(sle.Squad)
+}
+
+type CommanderTookRoleEvent struct {
+	GenericEvent
+	Team   TeamIdentifier `json:"team"`
+	Player PlayerInfo     `json:"player"`
+}
+
+func (ctre CommanderTookRoleEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{ctre.Player}
+}
+
+type CommanderLeftRoleEvent struct {
+	GenericEvent
+	Team   TeamIdentifier `json:"team"`
+	Player PlayerInfo     `json:"player"`
+}
+
+func (clre CommanderLeftRoleEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{clre.Player}
+}
+
+// a periodic copy of the polled server state, mainly meant for journals
+type ServerSnapshotEvent struct {
+	GenericEvent
+	SessionInfo SessionInfo          `json:"sessionInfo"`
+	Players     []DetailedPlayerInfo `json:"players"`
+}
+
+func (sse ServerSnapshotEvent) AffectedPlayers() []PlayerInfo {
+	players := []PlayerInfo{}
+	for _, player := range sse.Players {
+		players = append(players, player.PlayerInfo)
+	}
+	return players
+}
+
+type SessionClosedEvent struct {
+	GenericEvent
+	Session PlayerSession `json:"session"`
+}
+
+func (sce SessionClosedEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{sce.Session.Player}
+}
+
+type StrongpointEnteredEvent struct {
+	GenericEvent
+	Player      PlayerInfo     `json:"player"`
+	Team        TeamIdentifier `json:"team"`
+	Strongpoint Strongpoint    `json:"strongpoint"`
+	SectorIndex int            `json:"sectorIndex"` // index in Layer.OrderedSectors
+	Counts      TeamData       `json:"counts"`      // the players of each team inside the strongpoint after entering
+}
+
+func (see StrongpointEnteredEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{see.Player}
+}
+
+// also emitted when the player dies or leaves the server inside the strongpoint, or once the known layout drops it
+type StrongpointLeftEvent struct {
+	GenericEvent
+	Player      PlayerInfo     `json:"player"`
+	Team        TeamIdentifier `json:"team"`
+	Strongpoint Strongpoint    `json:"strongpoint"`
+	SectorIndex int            `json:"sectorIndex"` // index in Layer.OrderedSectors
+	Counts      TeamData       `json:"counts"`      // the players of each team inside the strongpoint after leaving
+}
+
+func (sle StrongpointLeftEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{sle.Player}
+}
+
+// emitted once a spawned player moves into the home sector of the enemy team, again only after the player left it
+type EnemyHQEnteredEvent struct {
+	GenericEvent
+	Player      PlayerInfo     `json:"player"`
+	Team        TeamIdentifier `json:"team"`        // the team of the player, not the one of the HQ
+	SectorIndex int            `json:"sectorIndex"` // index in Layer.OrderedSectors
+	Position    Position       `json:"position"`
+	Count       int            `json:"count"` // the players of the team inside the enemy home sector after entering
+}
+
+func (ehe EnemyHQEnteredEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{ehe.Player}
+}
+
+// emitted once an enemy comes within the radius of a watched player, again only after the enemy left that radius
+type EnemyProximityEvent struct {
+	GenericEvent
+	Rule     string         `json:"rule"` // the name of the proximity rule that matched
+	Player   PlayerInfo     `json:"player"`
+	Role     RoleIdentifier `json:"role"`
+	Enemy    PlayerInfo     `json:"enemy"`
+	Distance int            `json:"distance"` // the planar distance in cm
+}
+
+func (epe EnemyProximityEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{epe.Player, epe.Enemy}
+}
+
+type CrewFormedEvent struct {
+	GenericEvent
+	Crew VehicleCrew `json:"crew"`
+}
+
+func (cfe CrewFormedEvent) AffectedPlayers() []PlayerInfo {
+	return crewPlayers(cfe.Crew)
+}
+
+// the crew holds its members and position as of the last poll it was seen
+type CrewDissolvedEvent struct {
+	GenericEvent
+	Crew VehicleCrew `json:"crew"`
+}
+
+func (cde CrewDissolvedEvent) AffectedPlayers() []PlayerInfo {
+	return crewPlayers(cde.Crew)
+}
+
+func crewPlayers(crew VehicleCrew) []PlayerInfo {
+	players := []PlayerInfo{}
+	for _, member := range crew.Members {
+		players = append(players, member.PlayerInfo)
+	}
+	return players
+}
+
+type SuspicionKind string
+
+const (
+	SUSPICION_LONG_RANGE     SuspicionKind = "Long Range"     // the kill distance is far beyond the range of the weapon type
+	SUSPICION_FOREIGN_WEAPON SuspicionKind = "Foreign Weapon" // the weapon does not belong to the faction of the killer
+	SUSPICION_KILL_RATE      SuspicionKind = "Kill Rate"      // the killer kills far more often than the other players
+)
+
+type SuspicionEvidence struct {
+	Kind   SuspicionKind `json:"kind"`
+	Score  float64       `json:"score"`
+	Detail string        `json:"detail"`
+}
+
+// emitted for kills whose summed up evidence reaches the configured threshold, a hint for admins and no proof
+type SuspiciousKillEvent struct {
+	GenericEvent
+	Killer   PlayerInfo          `json:"killer"`
+	Victim   PlayerInfo          `json:"victim"`
+	Weapon   Weapon              `json:"weapon"`
+	Distance float64             `json:"distance"` // in meters; 0 if the position of either player is unknown
+	Score    float64             `json:"score"`
+	Evidence []SuspicionEvidence `json:"evidence"`
+}
+
+func (ske SuspiciousKillEvent) AffectedPlayers() []PlayerInfo {
+	return []PlayerInfo{ske.Killer, ske.Victim}
+}
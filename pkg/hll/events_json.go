@@ -0,0 +1,137 @@
+package hll
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// the version is increased whenever a change of an event breaks existing consumers,
+// the field names are fixed by the json tags of the event structs and not by their Go names
+const EventSchemaVersion = 1
+
+var eventRegistry = map[EventType]reflect.Type{
+	EVENT_CONNECTED:            reflect.TypeOf(ConnectEvent{}),
+	EVENT_DISCONNECTED:         reflect.TypeOf(DisconnectEvent{}),
+	EVENT_KILL:                 reflect.TypeOf(KillEvent{}),
+	EVENT_DEATH:                reflect.TypeOf(DeathEvent{}),
+	EVENT_TEAMKILL:             reflect.TypeOf(TeamKillEvent{}),
+	EVENT_TEAMDEATH:            reflect.TypeOf(TeamDeathEvent{}),
+	EVENT_CHAT:                 reflect.TypeOf(ChatEvent{}),
+	EVENT_BAN:                  reflect.TypeOf(BanEvent{}),
+	EVENT_KICK:                 reflect.TypeOf(KickEvent{}),
+	EVENT_MESSAGE:              reflect.TypeOf(MessageEvent{}),
+	EVENT_MATCHSTART:           reflect.TypeOf(MatchStartEvent{}),
+	EVENT_MATCHEND:             reflect.TypeOf(MatchEndEvent{}),
+	EVENT_ENTER_ADMINCAM:       reflect.TypeOf(AdminCamEnteredEvent{}),
+	EVENT_LEAVE_ADMINCAM:       reflect.TypeOf(AdminCamLeftEvent{}),
+	EVENT_VOTE_KICK_STARTED:    reflect.TypeOf(VoteStartedEvent{}),
+	EVENT_VOTE_SUBMITTED:       reflect.TypeOf(VoteSubmittedEvent{}),
+	EVENT_VOTE_KICK_COMPLETED:  reflect.TypeOf(VoteCompletedEvent{}),
+	EVENT_TEAM_SWITCHED:        reflect.TypeOf(PlayerSwitchTeamEvent{}),
+	EVENT_SQUAD_SWITCHED:       reflect.TypeOf(PlayerSwitchSquadEvent{}),
+	EVENT_SCORE_UPDATE:         reflect.TypeOf(PlayerScoreUpdateEvent{}),
+	EVENT_ROLE_CHANGED:         reflect.TypeOf(PlayerChangeRoleEvent{}),
+	EVENT_LOADOUT_CHANGED:      reflect.TypeOf(PlayerChangeLoadoutEvent{}),
+	EVENT_OBJECTIVE_CAPPED:     reflect.TypeOf(ObjectiveCaptureEvent{}),
+	EVENT_POSITION_CHANGED:     reflect.TypeOf(PlayerPositionChangedEvent{}),
+	EVENT_CLAN_TAG_CHANGED:     reflect.TypeOf(PlayerClanTagChangedEvent{}),
+	EVENT_PLAYER_SPAWNED:       reflect.TypeOf(PlayerSpawnedEvent{}),
+	EVENT_PLAYER_DESPAWNED:     reflect.TypeOf(PlayerDespawnedEvent{}),
+	EVENT_VEHICLE_DESTROYED:    reflect.TypeOf(PlayerVehicleDestroyedEvent{}),
+	EVENT_VEHICLE_KILL:         reflect.TypeOf(PlayerVehicleKillEvent{}),
+	EVENT_LEVEL_UP:             reflect.TypeOf(PlayerLevelUpEvent{}),
+	EVENT_KILL_STREAK:          reflect.TypeOf(KillStreakEvent{}),
+	EVENT_KILL_STREAK_ENDED:    reflect.TypeOf(KillStreakEndedEvent{}),
+	EVENT_MULTI_KILL:           reflect.TypeOf(MultiKillEvent{}),
+	EVENT_MATCH_PHASE_CHANGED:  reflect.TypeOf(MatchPhaseChangedEvent{}),
+	EVENT_SECTOR_CAPTURED:      reflect.TypeOf(SectorCapturedEvent{}),
+	EVENT_SERVER_FULL:          reflect.TypeOf(ServerFullEvent{}),
+	EVENT_SERVER_NOT_FULL:      reflect.TypeOf(ServerNotFullEvent{}),
+	EVENT_QUEUE_CHANGED:        reflect.TypeOf(QueueChangedEvent{}),
+	EVENT_VIP_QUEUE_CHANGED:    reflect.TypeOf(VIPQueueChangedEvent{}),
+	EVENT_POPULATION_CROSSED:   reflect.TypeOf(PopulationThresholdCrossedEvent{}),
+	EVENT_SQUAD_CREATED:        reflect.TypeOf(SquadCreatedEvent{}),
+	EVENT_SQUAD_DISBANDED:      reflect.TypeOf(SquadDisbandedEvent{}),
+	EVENT_SQUAD_LEADER_CHANGED: reflect.TypeOf(SquadLeaderChangedEvent{}),
+	EVENT_SQUAD_LEADERLESS:     reflect.TypeOf(SquadLeaderlessEvent{}),
+	EVENT_COMMANDER_TOOK_ROLE:  reflect.TypeOf(CommanderTookRoleEvent{}),
+	EVENT_COMMANDER_LEFT_ROLE:  reflect.TypeOf(CommanderLeftRoleEvent{}),
+	EVENT_SERVER_SNAPSHOT:      reflect.TypeOf(ServerSnapshotEvent{}),
+	EVENT_SESSION_CLOSED:       reflect.TypeOf(SessionClosedEvent{}),
+	EVENT_STRONGPOINT_ENTERED:  reflect.TypeOf(StrongpointEnteredEvent{}),
+	EVENT_STRONGPOINT_LEFT:     reflect.TypeOf(StrongpointLeftEvent{}),
+	EVENT_ENEMY_HQ_ENTERED:     reflect.TypeOf(EnemyHQEnteredEvent{}),
+	EVENT_ENEMY_PROXIMITY:      reflect.TypeOf(EnemyProximityEvent{}),
+	EVENT_CREW_FORMED:          reflect.TypeOf(CrewFormedEvent{}),
+	EVENT_CREW_DISSOLVED:       reflect.TypeOf(CrewDissolvedEvent{}),
+	EVENT_SUSPICIOUS_KILL:      reflect.TypeOf(SuspiciousKillEvent{}),
+	EVENT_GENERIC:              reflect.TypeOf(GenericEvent{}),
+}
+
+// all event types that can be serialized, sorted by name
+func EventTypes() []EventType {
+	eventTypes := []EventType{}
+	for eventType := range eventRegistry {
+		eventTypes = append(eventTypes, eventType)
+	}
+	slices.Sort(eventTypes)
+	return eventTypes
+}
+
+// returns the zero value of the event struct belonging to the type
+func NewEvent(eventType EventType) (Event, error) {
+	eventStruct, ok := eventRegistry[eventType]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+	return reflect.New(eventStruct).Elem().Interface().(Event), nil
+}
+
+type eventEnvelope struct {
+	Version int             `json:"version"`
+	Type    EventType       `json:"type"`
+	Event   json.RawMessage `json:"event"`
+}
+
+// the event is wrapped in an envelope holding the schema version and the event type
+func MarshalEvent(event Event) ([]byte, error) {
+	eventStruct, ok := eventRegistry[event.Type()]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", event.Type())
+	}
+	if reflect.TypeOf(event) != eventStruct {
+		return nil, fmt.Errorf("event type %q does not belong to %T", event.Type(), event)
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(eventEnvelope{
+		Version: EventSchemaVersion,
+		Type:    event.Type(),
+		Event:   data,
+	})
+}
+
+// returns the concrete event struct, e.g. a KillEvent for the type EVENT_KILL
+func UnmarshalEvent(data []byte) (Event, error) {
+	var envelope eventEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Version < 1 || envelope.Version > EventSchemaVersion {
+		return nil, fmt.Errorf("unsupported event schema version %d", envelope.Version)
+	}
+
+	eventStruct, ok := eventRegistry[envelope.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", envelope.Type)
+	}
+	event := reflect.New(eventStruct)
+	if err := json.Unmarshal(envelope.Event, event.Interface()); err != nil {
+		return nil, err
+	}
+	return event.Elem().Interface().(Event), nil
+}
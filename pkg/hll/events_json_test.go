@@ -0,0 +1,72 @@
+package hll
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// the fixtures are written by hand, every field has to be listed so that a renamed or removed field breaks the test
+func TestEventFixtures(t *testing.T) {
+	for _, eventType := range EventTypes() {
+		t.Run(string(eventType), func(t *testing.T) {
+			fileName := strings.ReplaceAll(strings.ToLower(string(eventType)), " ", "_") + ".json"
+			fixture, err := os.ReadFile(filepath.Join("testdata", "events", fileName))
+			if err != nil {
+				t.Fatalf("Expected fixture %s, but got %v", fileName, err)
+			}
+
+			event, err := UnmarshalEvent(fixture)
+			if err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+			if event.Type() != eventType || reflect.TypeOf(event) != eventRegistry[eventType] {
+				t.Fatalf("Expected %s, but got %T of type %s", eventRegistry[eventType], event, event.Type())
+			}
+			if event.Time().IsZero() || event.Sequence() == 0 || event.Source() == EVENT_SOURCE_UNKNOWN {
+				t.Errorf("Expected the metadata to be set, but got %v, %d and %q", event.Time(), event.Sequence(), event.Source())
+			}
+
+			data, err := MarshalEvent(event)
+			if err != nil {
+				t.Fatalf("Expected no error, but got %v", err)
+			}
+			var expected, actual any
+			if err := json.Unmarshal(fixture, &expected); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(data, &actual); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("Expected %s to match the serialized event, but got %s", fileName, data)
+			}
+		})
+	}
+}
+
+func TestUnmarshalEventErrors(t *testing.T) {
+	tests := map[string]string{
+		"invalid json":    `{"version":`,
+		"unknown type":    `{"version":1,"type":"UNKNOWN","event":{}}`,
+		"missing version": `{"type":"KILL","event":{}}`,
+		"future version":  `{"version":999,"type":"KILL","event":{}}`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := UnmarshalEvent([]byte(data)); err == nil {
+				t.Error("Expected an error, but got none")
+			}
+		})
+	}
+}
+
+func TestMarshalEventMismatchedType(t *testing.T) {
+	event := KillEvent{GenericEvent: GenericEvent{EventType: EVENT_DEATH}}
+	if _, err := MarshalEvent(event); err == nil {
+		t.Error("Expected an error for a mismatched event type, but got none")
+	}
+}
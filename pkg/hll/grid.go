@@ -0,0 +1,106 @@
+package hll
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// the in-game reference of a grid square, optionally narrowed down to one of its keypad sub-squares
+type GridReference struct {
+	Column int // 0 based from the left, i.e. A is 0
+	Row    int // 0 based from the top, i.e. 1 is 0
+	Keypad int // 1-9 laid out like a phone keypad with 1 in the top left, 0 for the whole square
+}
+
+// formatted as shown on the in-game map, e.g. E5 or E5-3
+func (gr GridReference) String() string {
+	label := fmt.Sprintf("%c%d", 'A'+gr.Column, gr.Row+1)
+	if gr.Keypad > 0 {
+		label += fmt.Sprintf("-%d", gr.Keypad)
+	}
+	return label
+}
+
+var gridReferencePattern = regexp.MustCompile(`^([A-Za-z])\s*(\d{1,2})(?:\s*(?:-|\s|kp|KP|Kp)\s*([1-9]))?$`)
+
+// accepts the forms E5, E5-3, E5 3 and E5 kp3
+func ParseGridReference(label string) (GridReference, error) {
+	match := gridReferencePattern.FindStringSubmatch(strings.TrimSpace(label))
+	if match == nil {
+		return GridReference{}, fmt.Errorf("invalid grid reference: %s", label)
+	}
+	row, _ := strconv.Atoi(match[2])
+	if row < 1 {
+		return GridReference{}, fmt.Errorf("invalid grid reference: %s", label)
+	}
+	keypad := 0
+	if match[3] != "" {
+		keypad, _ = strconv.Atoi(match[3])
+	}
+	return GridReference{
+		Column: int(strings.ToUpper(match[1])[0] - 'A'),
+		Row:    row - 1,
+		Keypad: keypad,
+	}, nil
+}
+
+// the reference of the keypad sub-square containing the position
+func (g Grid) Reference(pos Position) (GridReference, error) {
+	coord, err := g.WorldToGrid(pos.X, pos.Y)
+	if err != nil {
+		return GridReference{}, err
+	}
+	minX, minY := g.GridToWorldMin(coord)
+	subSize := g.Scale / 3
+	keypadX := min(max(int(math.Floor((pos.X-minX)/subSize)), 0), 2)
+	keypadY := min(max(int(math.Floor((pos.Y-minY)/subSize)), 0), 2)
+	return GridReference{
+		Column: coord.X - g.Min.X,
+		Row:    coord.Y - g.Min.Y,
+		Keypad: keypadY*3 + keypadX + 1,
+	}, nil
+}
+
+// the world bounds of the referenced square or keypad sub-square, the Z coordinates are left at zero
+func (g Grid) Bounds(ref GridReference) (Position, Position, error) {
+	coord := GridCoordinate{X: g.Min.X + ref.Column, Y: g.Min.Y + ref.Row}
+	if ref.Column < 0 || ref.Row < 0 || !g.IsInside(coord) {
+		return Position{}, Position{}, fmt.Errorf("grid reference out of bounds: %s", ref)
+	}
+	if ref.Keypad < 0 || ref.Keypad > 9 {
+		return Position{}, Position{}, fmt.Errorf("invalid keypad: %d", ref.Keypad)
+	}
+
+	minX, minY := g.GridToWorldMin(coord)
+	maxX, maxY := g.GridToWorldMax(coord)
+	if ref.Keypad > 0 {
+		subSize := g.Scale / 3
+		minX += float64((ref.Keypad-1)%3) * subSize
+		minY += float64((ref.Keypad-1)/3) * subSize
+		maxX, maxY = minX+subSize, minY+subSize
+	}
+	return Position{X: minX, Y: minY}, Position{X: maxX, Y: maxY}, nil
+}
+
+// the in-game grid reference of the position on this layer
+func (l Layer) GridReference(pos Position) (GridReference, error) {
+	if l.Grid.Scale <= 0 {
+		return GridReference{}, ErrNoGrid
+	}
+	return l.Grid.Reference(pos)
+}
+
+// the world bounds of the grid label on this layer, e.g. E5 or E5-3
+func (l Layer) GridBounds(label string) (Position, Position, error) {
+	if l.Grid.Scale <= 0 {
+		return Position{}, Position{}, ErrNoGrid
+	}
+	ref, err := ParseGridReference(label)
+	if err != nil {
+		return Position{}, Position{}, err
+	}
+	return l.Grid.Bounds(ref)
+}
@@ -0,0 +1,78 @@
+package hll
+
+import "testing"
+
+func TestGridReference(t *testing.T) {
+	layer := LAYER_CARENTAN_WARFARE.Layer()
+
+	t.Run("position to label", func(t *testing.T) {
+		tests := []struct {
+			position Position
+			expected string
+		}{
+			{Position{X: -100000, Y: -100000}, "A1-1"},
+			{Position{X: -1, Y: -1}, "E5-9"},
+			{Position{X: 1, Y: 1}, "F6-1"},
+			{Position{X: -65000, Y: -39000}, "B4-3"},
+			{Position{X: 100000, Y: 100000}, "J10-9"},
+			{Position{X: 10000, Y: -3000}, "F5-8"},
+		}
+		for _, test := range tests {
+			ref, err := layer.GridReference(test.position)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ref.String() != test.expected {
+				t.Errorf("Expected %s for %v, but got %s", test.expected, test.position, ref)
+			}
+		}
+		if _, err := layer.GridReference(Position{X: 300000}); err == nil {
+			t.Error("Expected an error for a position outside of the grid")
+		}
+	})
+
+	t.Run("label to bounds", func(t *testing.T) {
+		min, max, err := layer.GridBounds("F6")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if min != (Position{X: 0, Y: 0}) || max != (Position{X: 20160, Y: 20160}) {
+			t.Errorf("Expected F6 to span (0, 0) to (20160, 20160), but got %v to %v", min, max)
+		}
+
+		min, max, err = layer.GridBounds("e5 kp9")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if min != (Position{X: -6720, Y: -6720}) || max != (Position{X: 0, Y: 0}) {
+			t.Errorf("Expected E5-9 to span (-6720, -6720) to (0, 0), but got %v to %v", min, max)
+		}
+
+		for _, label := range []string{"K1", "A11", "A0", "E", "E5-0", "55"} {
+			if _, _, err := layer.GridBounds(label); err == nil {
+				t.Errorf("Expected an error for %s", label)
+			}
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, label := range []string{"A1", "C7-4", "J10-9", "E5 2"} {
+			min, max, err := layer.GridBounds(label)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ref, _ := ParseGridReference(label)
+			center := Position{X: (min.X + max.X) / 2, Y: (min.Y + max.Y) / 2}
+			actual, err := layer.GridReference(center)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ref.Keypad == 0 {
+				actual.Keypad = 0
+			}
+			if actual != ref {
+				t.Errorf("Expected %s, but got %s", ref, actual)
+			}
+		}
+	})
+}
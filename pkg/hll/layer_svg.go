@@ -0,0 +1,204 @@
+package hll
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"maps"
+	"slices"
+)
+
+const defaultSquareSize = 64
+
+const (
+	svgBackground  = "#202225"
+	svgGridLine    = "#707070"
+	svgSector      = "#c0c0c0"
+	svgCaptureZone = "#ffffff"
+	svgStrongpoint = "#ffffff"
+	svgAllies      = "#4a90d9"
+	svgAxis        = "#d9534f"
+)
+
+// returned when rendering or locating positions on a layer whose grid is unknown
+var ErrNoGrid = errors.New("layer without a grid")
+
+type MapRenderConfig struct {
+	SquareSize  int         // the size of one grid square in pixels, 64 if not positive
+	View        *ServerView // if set, the spawned players are plotted on the map
+	PlayerNames bool        // label the plotted players with their names
+}
+
+// renders the sector layout of the layer with grid labels as SVG
+func (l Layer) RenderSVG(w io.Writer, cfg MapRenderConfig) error {
+	if l.Grid.Scale <= 0 {
+		return ErrNoGrid
+	}
+	if cfg.SquareSize <= 0 {
+		cfg.SquareSize = defaultSquareSize
+	}
+	writer := bufio.NewWriter(w)
+	width, height := l.svgSize(cfg.SquareSize)
+
+	fmt.Fprintf(writer, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n", width, height, width, height)
+	fmt.Fprintf(writer, "<title>%s</title>\n", html.EscapeString(l.PrettyName))
+	fmt.Fprintf(writer, `<rect width="%d" height="%d" fill="%s"/>`+"\n", width, height, svgBackground)
+	if err := l.WriteSVGLayout(writer, cfg.SquareSize); err != nil {
+		return err
+	}
+	if cfg.View != nil {
+		l.writeSVGTeam(writer, cfg, TEAM_ALLIES, cfg.View.Allies)
+		l.writeSVGTeam(writer, cfg, TEAM_AXIS, cfg.View.Axis)
+	}
+	fmt.Fprintln(writer, "</svg>")
+	return writer.Flush()
+}
+
+// writes the grid, the sectors and the strongpoints as SVG elements to embed them into another rendering of the layer
+func (l Layer) WriteSVGLayout(w io.Writer, squareSize int) error {
+	if l.Grid.Scale <= 0 {
+		return ErrNoGrid
+	}
+	if squareSize <= 0 {
+		squareSize = defaultSquareSize
+	}
+	width, height := l.svgSize(squareSize)
+	columns, rows := width/squareSize, height/squareSize
+
+	sectors, _ := l.Sectors()
+	fmt.Fprintln(w, `<g class="sectors">`)
+	for _, sector := range sectors {
+		l.writeSVGArea(w, squareSize, sector.From, sector.To, svgSector, "0", 2)
+		for _, zone := range sector.CaptureZones {
+			l.writeSVGArea(w, squareSize, zone.From, zone.To, svgCaptureZone, "0.06", 0)
+		}
+	}
+	fmt.Fprintln(w, "</g>")
+
+	fmt.Fprintln(w, `<g class="grid">`)
+	for i := 0; i <= columns; i++ {
+		x := i * squareSize
+		fmt.Fprintf(w, `<line x1="%d" y1="0" x2="%d" y2="%d" stroke="%s" stroke-width="1"/>`+"\n", x, x, height, svgGridLine)
+		if i < columns {
+			fmt.Fprintf(w, `<text x="%d" y="12" font-size="11" fill="%s">%c</text>`+"\n", x+3, svgGridLine, 'A'+i)
+		}
+	}
+	for i := 0; i <= rows; i++ {
+		y := i * squareSize
+		fmt.Fprintf(w, `<line x1="0" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"/>`+"\n", y, width, y, svgGridLine)
+		if i < rows {
+			fmt.Fprintf(w, `<text x="3" y="%d" font-size="11" fill="%s">%d</text>`+"\n", y+squareSize-4, svgGridLine, i+1)
+		}
+	}
+	fmt.Fprintln(w, "</g>")
+
+	fmt.Fprintln(w, `<g class="strongpoints">`)
+	for _, strongpoint := range l.Strongpoints() {
+		x, y := l.ToPixel(strongpoint.Center, squareSize)
+		radius := strongpoint.Radius / l.Grid.Scale * float64(squareSize)
+		fmt.Fprintf(w, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="none" stroke="%s" stroke-width="1.5" stroke-dasharray="4 3"/>`+"\n",
+			x, y, radius, svgStrongpoint)
+		fmt.Fprintf(w, `<text x="%.1f" y="%.1f" font-size="10" text-anchor="middle" fill="%s">%s</text>`+"\n",
+			x, y+radius+11, svgStrongpoint, html.EscapeString(strongpoint.Name))
+	}
+	_, err := fmt.Fprintln(w, "</g>")
+	return err
+}
+
+// the pixel position of the world position in a rendering with the given size of a grid square
+func (l Layer) ToPixel(pos Position, squareSize int) (float64, float64) {
+	minX, minY := l.Grid.GridToWorldMin(l.Grid.Min)
+	scale := float64(squareSize) / l.Grid.Scale
+	return (pos.X - minX) * scale, (pos.Y - minY) * scale
+}
+
+func (l Layer) svgSize(squareSize int) (int, int) {
+	return (l.Grid.Max.X - l.Grid.Min.X + 1) * squareSize, (l.Grid.Max.Y - l.Grid.Min.Y + 1) * squareSize
+}
+
+// the grid coordinates of the area are inclusive
+func (l Layer) writeSVGArea(w io.Writer, squareSize int, from, to GridCoordinate, color string, opacity string, strokeWidth int) {
+	x := (from.X - l.Grid.Min.X) * squareSize
+	y := (from.Y - l.Grid.Min.Y) * squareSize
+	width := (to.X - from.X + 1) * squareSize
+	height := (to.Y - from.Y + 1) * squareSize
+	fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" fill-opacity="%s" stroke="%s" stroke-width="%d"/>`+"\n",
+		x, y, width, height, color, opacity, color, strokeWidth)
+}
+
+// every squad is a group with lines from the members to the leader, the commander is drawn as a diamond
+func (l Layer) writeSVGTeam(w io.Writer, cfg MapRenderConfig, team TeamIdentifier, view *TeamView) {
+	if view == nil {
+		return
+	}
+	color := svgAllies
+	if team == TEAM_AXIS {
+		color = svgAxis
+	}
+
+	fmt.Fprintf(w, `<g class="team" data-team="%s">`+"\n", team)
+	for _, name := range slices.Sorted(maps.Keys(view.Squads)) {
+		squad := view.Squads[name]
+		fmt.Fprintf(w, `<g class="squad" data-squad="%s">`+"\n", html.EscapeString(name))
+		leader, hasLeader := squad.Leader()
+		if hasLeader && leader.Position.IsActive() {
+			lx, ly := l.ToPixel(leader.Position, cfg.SquareSize)
+			for _, player := range squad.Players {
+				if player.ID == leader.ID || !player.Position.IsActive() {
+					continue
+				}
+				x, y := l.ToPixel(player.Position, cfg.SquareSize)
+				fmt.Fprintf(w, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-opacity="0.4" stroke-width="1"/>`+"\n", lx, ly, x, y, color)
+			}
+			fmt.Fprintf(w, `<text x="%.1f" y="%.1f" font-size="9" font-weight="bold" fill="%s">%s</text>`+"\n", lx+6, ly-6, color, html.EscapeString(name))
+		}
+		for _, player := range squad.Players {
+			l.writeSVGPlayer(w, cfg, player, color, hasLeader && player.ID == leader.ID)
+		}
+		fmt.Fprintln(w, "</g>")
+	}
+	if view.Commander.ID != "" {
+		fmt.Fprintln(w, `<g class="commander">`)
+		l.writeSVGCommander(w, cfg, view.Commander, color)
+		fmt.Fprintln(w, "</g>")
+	}
+	fmt.Fprintln(w, "</g>")
+}
+
+func (l Layer) writeSVGPlayer(w io.Writer, cfg MapRenderConfig, player DetailedPlayerInfo, color string, leader bool) {
+	if !player.Position.IsActive() {
+		return
+	}
+	radius := 3
+	if leader {
+		radius = 5
+	}
+	x, y := l.ToPixel(player.Position, cfg.SquareSize)
+	fmt.Fprintf(w, `<circle cx="%.1f" cy="%.1f" r="%d" fill="%s" stroke="#000000" stroke-width="0.5"><title>%s</title></circle>`+"\n",
+		x, y, radius, color, svgPlayerTitle(player))
+	l.writeSVGName(w, cfg, player, color)
+}
+
+func (l Layer) writeSVGCommander(w io.Writer, cfg MapRenderConfig, player DetailedPlayerInfo, color string) {
+	if !player.Position.IsActive() {
+		return
+	}
+	x, y := l.ToPixel(player.Position, cfg.SquareSize)
+	fmt.Fprintf(w, `<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="%s" stroke="#ffffff" stroke-width="1"><title>%s</title></polygon>`+"\n",
+		x, y-7, x+7, y, x, y+7, x-7, y, color, svgPlayerTitle(player))
+	l.writeSVGName(w, cfg, player, color)
+}
+
+func (l Layer) writeSVGName(w io.Writer, cfg MapRenderConfig, player DetailedPlayerInfo, color string) {
+	if !cfg.PlayerNames {
+		return
+	}
+	x, y := l.ToPixel(player.Position, cfg.SquareSize)
+	fmt.Fprintf(w, `<text x="%.1f" y="%.1f" font-size="8" text-anchor="middle" fill="%s">%s</text>`+"\n", x, y+14, color, html.EscapeString(player.Name))
+}
+
+func svgPlayerTitle(player DetailedPlayerInfo) string {
+	return html.EscapeString(fmt.Sprintf("%s (%s)", player.Name, player.Role))
+}
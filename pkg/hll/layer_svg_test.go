@@ -0,0 +1,59 @@
+package hll
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderSVG(t *testing.T) {
+	layer := LAYER_CARENTAN_WARFARE.Layer()
+	player := func(name string, team TeamIdentifier, unit Unit, role RoleIdentifier, x, y float64) DetailedPlayerInfo {
+		return DetailedPlayerInfo{
+			PlayerInfo: PlayerInfo{Name: name, ID: name},
+			Team:       team,
+			Unit:       unit,
+			Role:       role,
+			Position:   Position{X: x, Y: y, Z: 100},
+		}
+	}
+	view := PlayersToServerView([]DetailedPlayerInfo{
+		player("Alice", TEAM_ALLIES, Unit{ID: 0, Name: "Able"}, ROLE_OFFICER, -1000, -1000),
+		player("Bob", TEAM_ALLIES, Unit{ID: 0, Name: "Able"}, ROLE_RIFLEMAN, 2000, 2000),
+		player("Carol", TEAM_AXIS, CommandUnit, ROLE_ARMYCOMMANDER, 30000, 30000),
+		player("Dave", TEAM_AXIS, Unit{ID: 1, Name: "Baker"}, ROLE_MEDIC, 0, 0), // not spawned
+	})
+	view.Axis.Squads["Baker"].Players[0].Position = Position{}
+
+	var svg bytes.Buffer
+	if err := layer.RenderSVG(&svg, MapRenderConfig{View: view, PlayerNames: true}); err != nil {
+		t.Fatal(err)
+	}
+	rendered := svg.String()
+
+	for _, expected := range []string{
+		`width="640" height="640"`,
+		"<title>Carentan Warfare</title>",
+		">Blactot<",
+		">J<",
+		">10<",
+		`data-squad="Able"`,
+		"<title>Alice (Officer)</title>",
+		`<g class="commander">`,
+		"<title>Carol (ArmyCommander)</title></polygon>",
+	} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("Expected the SVG to contain %q", expected)
+		}
+	}
+	if strings.Contains(rendered, "Dave") {
+		t.Error("Expected players that are not spawned to be left out")
+	}
+	if count := strings.Count(rendered, "<circle"); count != len(layer.Strongpoints())+2 {
+		t.Errorf("Expected %d circles, but got %d", len(layer.Strongpoints())+2, count)
+	}
+
+	if err := (Layer{}).RenderSVG(&svg, MapRenderConfig{}); err == nil {
+		t.Error("Expected an error for a layer without a grid")
+	}
+}
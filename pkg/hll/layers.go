@@ -2,6 +2,7 @@ package hll
 
 import (
 	"fmt"
+	"math"
 	"strings"
 )
 
@@ -180,16 +181,16 @@ const (
 )
 
 type GridCoordinate struct {
-	X int
-	Y int
+	X int `json:"x"`
+	Y int `json:"y"`
 }
 
 type Grid struct {
-	Scale   float64
-	OffsetX float64
-	OffsetY float64
-	Min     GridCoordinate
-	Max     GridCoordinate
+	Scale   float64        `json:"scale"`
+	OffsetX float64        `json:"offsetX"`
+	OffsetY float64        `json:"offsetY"`
+	Min     GridCoordinate `json:"min"`
+	Max     GridCoordinate `json:"max"`
 }
 
 func (g Grid) IsInside(coord GridCoordinate) bool {
@@ -215,8 +216,9 @@ func (g Grid) GridToWorldMax(coord GridCoordinate) (float64, float64) {
 }
 
 func (g Grid) WorldToGrid(x, y float64) (GridCoordinate, error) {
-	gridX := int((x - g.OffsetX) / g.Scale)
-	gridY := int((y - g.OffsetY) / g.Scale)
+	// rounding towards zero would merge the squares left and right of the origin
+	gridX := int(math.Floor((x - g.OffsetX) / g.Scale))
+	gridY := int(math.Floor((y - g.OffsetY) / g.Scale))
 	coord := GridCoordinate{X: gridX, Y: gridY}
 	if !g.IsInside(coord) {
 		return GridCoordinate{}, fmt.Errorf("coordinate out of bounds: %v", coord)
@@ -225,18 +227,18 @@ func (g Grid) WorldToGrid(x, y float64) (GridCoordinate, error) {
 }
 
 type Layer struct {
-	ID                 LayerIdentifier
-	MapIdentifier      MapIdentifier
-	GameModeIdentifier GameModeIdentifier
-	TimeOfDay          TimeOfDay
-	Weather            Weather
-	PrettyName         string
-	Grid               Grid
-	SectorsIdentifier  SectorsIdentifier
-	AttackingTeam      TeamIdentifier
-	DefendingTeam      TeamIdentifier
-	AttackingFaction   FactionIdentifier
-	DefendingFaction   FactionIdentifier
+	ID                 LayerIdentifier    `json:"id"`
+	MapIdentifier      MapIdentifier      `json:"mapIdentifier"`
+	GameModeIdentifier GameModeIdentifier `json:"gameModeIdentifier"`
+	TimeOfDay          TimeOfDay          `json:"timeOfDay"`
+	Weather            Weather            `json:"weather"`
+	PrettyName         string             `json:"prettyName"`
+	Grid               Grid               `json:"grid"`
+	SectorsIdentifier  SectorsIdentifier  `json:"sectorsIdentifier"`
+	AttackingTeam      TeamIdentifier     `json:"attackingTeam"`
+	DefendingTeam      TeamIdentifier     `json:"defendingTeam"`
+	AttackingFaction   FactionIdentifier  `json:"attackingFaction"`
+	DefendingFaction   FactionIdentifier  `json:"defendingFaction"`
 }
 
 var layerMap = map[LayerIdentifier]Layer{
@@ -2877,6 +2879,34 @@ func (l Layer) Sectors() ([]Sector, error) {
 	return []Sector{}, fmt.Errorf("sectors not found for layer: %s", l.ID)
 }
 
+func (l Layer) Strongpoints() []Strongpoint {
+	strongpoints := []Strongpoint{}
+	sectors, err := l.Sectors()
+	if err != nil {
+		return strongpoints
+	}
+	for _, sector := range sectors {
+		for _, zone := range sector.CaptureZones {
+			strongpoints = append(strongpoints, zone.Strongpoint)
+		}
+	}
+	return strongpoints
+}
+
+func (l Layer) NearestStrongpoint(pos Position) (Strongpoint, error) {
+	strongpoints := l.Strongpoints()
+	if len(strongpoints) == 0 {
+		return Strongpoint{}, fmt.Errorf("no strongpoints found for layer: %s", l.ID)
+	}
+	nearest := strongpoints[0]
+	for _, sp := range strongpoints[1:] {
+		if pos.PlanarDistanceTo(sp.Center) < pos.PlanarDistanceTo(nearest.Center) {
+			nearest = sp
+		}
+	}
+	return nearest, nil
+}
+
 func AllLayers() []Layer {
 	layers := []Layer{}
 	for _, l := range layerMap {
@@ -38,15 +38,15 @@ const (
 )
 
 type Map struct {
-	ID               MapIdentifier
-	Name             string
-	Tag              string
-	PrettyName       string
-	ShortName        string
-	Allies           FactionIdentifier
-	Axis             FactionIdentifier
-	Orientation      Orientation // Whether the sectors are arranged horizontally (left-to-right) or vertically (top-to-bottom)
-	MirroredFactions bool        // If the start side of the factions is mirrored or not. By default, Allies start left/top and Axis start right/bottom
+	ID               MapIdentifier     `json:"id"`
+	Name             string            `json:"name"`
+	Tag              string            `json:"tag"`
+	PrettyName       string            `json:"prettyName"`
+	ShortName        string            `json:"shortName"`
+	Allies           FactionIdentifier `json:"allies"`
+	Axis             FactionIdentifier `json:"axis"`
+	Orientation      Orientation       `json:"orientation"`      // Whether the sectors are arranged horizontally (left-to-right) or vertically (top-to-bottom)
+	MirroredFactions bool              `json:"mirroredFactions"` // If the start side of the factions is mirrored or not. By default, Allies start left/top and Axis start right/bottom
 }
 
 var mapMap = map[MapIdentifier]Map{
@@ -27,8 +27,8 @@ const (
 )
 
 type Unit struct {
-	Name string
-	ID   int
+	Name string `json:"name"`
+	ID   int    `json:"id"`
 }
 
 var (
@@ -76,10 +76,10 @@ const (
 )
 
 type Score struct {
-	Combat  int
-	Offense int
-	Defense int
-	Support int
+	Combat  int `json:"combat"`
+	Offense int `json:"offense"`
+	Defense int `json:"defense"`
+	Support int `json:"support"`
 }
 
 func (s Score) GetScoreValue(scoreCategory ScoreCategory) int {
@@ -97,8 +97,8 @@ func (s Score) GetScoreValue(scoreCategory ScoreCategory) int {
 }
 
 type PlayerInfo struct {
-	Name string
-	ID   string
+	Name string `json:"name"`
+	ID   string `json:"id"`
 }
 
 type AdminRole string
@@ -179,21 +179,21 @@ func PlayerPlatformFromString(name string) PlayerPlatform {
 
 type DetailedPlayerInfo struct {
 	PlayerInfo
-	ClanTag           string
-	Platform          PlayerPlatform
-	Team              TeamIdentifier
-	Faction           FactionIdentifier
-	Role              RoleIdentifier
-	Unit              Unit
-	Loadout           string
-	Kills             int
-	Deaths            int
-	TeamKills         int
-	VehicleKills      int
-	VehiclesDestroyed int
-	Score             Score
-	Level             int
-	Position          Position
+	ClanTag           string            `json:"clanTag"`
+	Platform          PlayerPlatform    `json:"platform"`
+	Team              TeamIdentifier    `json:"team"`
+	Faction           FactionIdentifier `json:"faction"`
+	Role              RoleIdentifier    `json:"role"`
+	Unit              Unit              `json:"unit"`
+	Loadout           string            `json:"loadout"`
+	Kills             int               `json:"kills"`
+	Deaths            int               `json:"deaths"`
+	TeamKills         int               `json:"teamKills"`
+	VehicleKills      int               `json:"vehicleKills"`
+	VehiclesDestroyed int               `json:"vehiclesDestroyed"`
+	Score             Score             `json:"score"`
+	Level             int               `json:"level"`
+	Position          Position          `json:"position"`
 }
 
 func EmptyDetailedPlayerInfo() DetailedPlayerInfo {
@@ -12,9 +12,9 @@ const (
 )
 
 type Position struct {
-	X float64
-	Y float64
-	Z float64
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
 }
 
 func (p Position) String() string {
@@ -67,6 +67,7 @@ func (p Position) IsWithinMapBounds() bool {
 		p.Y >= MAP_MIN && p.Y <= MAP_MAX
 }
 
+// assumes a 10x10 grid over the whole map, Layer.GridReference matches the in-game map of a layer
 func (p Position) ToGridReference() string {
 	if !p.IsActive() {
 		return "N/A"
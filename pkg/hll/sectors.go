@@ -1,5 +1,11 @@
 package hll
 
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
 type SectorsIdentifier string
 
 const (
@@ -45,10 +51,10 @@ func (s SectorsIdentifier) Sectors() []Sector {
 }
 
 type Strongpoint struct {
-	ID     string
-	Name   string
-	Center Position
-	Radius float64
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Center Position `json:"center"`
+	Radius float64  `json:"radius"`
 }
 
 func (s Strongpoint) IsInside(pos Position) bool {
@@ -59,16 +65,89 @@ func (s Strongpoint) IsInside(pos Position) bool {
 	return distanceSquared <= s.Radius*s.Radius
 }
 
-type CaptureZone struct {
-	From        GridCoordinate
-	To          GridCoordinate
+// the players of each team inside a strongpoint
+type StrongpointPresence struct {
 	Strongpoint Strongpoint
+	SectorIndex int // index in Layer.OrderedSectors
+	Allies      []PlayerInfo
+	Axis        []PlayerInfo
+}
+
+func (sp StrongpointPresence) Counts() TeamData {
+	return TeamData{Allies: len(sp.Allies), Axis: len(sp.Axis)}
+}
+
+// both teams have players inside the strongpoint
+func (sp StrongpointPresence) IsContested() bool {
+	return len(sp.Allies) > 0 && len(sp.Axis) > 0
+}
+
+type CaptureZone struct {
+	From        GridCoordinate `json:"from"`
+	To          GridCoordinate `json:"to"`
+	Strongpoint Strongpoint    `json:"strongpoint"`
 }
 
 type Sector struct {
-	From         GridCoordinate
-	To           GridCoordinate
-	CaptureZones []CaptureZone
+	From         GridCoordinate `json:"from"`
+	To           GridCoordinate `json:"to"`
+	CaptureZones []CaptureZone  `json:"captureZones"`
+}
+
+// the name is matched case-insensitive against the ID and name of the strongpoints
+func (s Sector) StrongpointByName(name string) (Strongpoint, bool) {
+	for _, zone := range s.CaptureZones {
+		if strings.EqualFold(zone.Strongpoint.ID, name) || strings.EqualFold(zone.Strongpoint.Name, name) {
+			return zone.Strongpoint, true
+		}
+	}
+	return Strongpoint{}, false
+}
+
+// OrderedSectors returns the sectors of the layer ordered in the direction of the map orientation,
+// i.e. left-to-right for horizontal and top-to-bottom for vertical maps
+func (l Layer) OrderedSectors() ([]Sector, error) {
+	sectors, err := l.Sectors()
+	if err != nil {
+		return sectors, err
+	}
+	ordered := slices.Clone(sectors)
+	if l.MapIdentifier.Map().Orientation == ORIENTATION_VERTICAL {
+		slices.SortStableFunc(ordered, func(a, b Sector) int { return a.From.Y - b.From.Y })
+	} else {
+		slices.SortStableFunc(ordered, func(a, b Sector) int { return a.From.X - b.From.X })
+	}
+	return ordered, nil
+}
+
+// CapturedSector infers which sector changed hands from the change of the game score.
+// The returned index refers to the sectors as returned by OrderedSectors.
+func (l Layer) CapturedSector(oldScore TeamData, newScore TeamData) (TeamIdentifier, int, error) {
+	sectors, err := l.OrderedSectors()
+	if err != nil {
+		return TEAM_NONE, -1, err
+	}
+
+	var team TeamIdentifier
+	var held int
+	switch {
+	case newScore.Allies == oldScore.Allies+1 && newScore.Axis <= oldScore.Axis:
+		team, held = TEAM_ALLIES, newScore.Allies
+	case newScore.Axis == oldScore.Axis+1 && newScore.Allies <= oldScore.Allies:
+		team, held = TEAM_AXIS, newScore.Axis
+	default:
+		return TEAM_NONE, -1, errors.New("score change does not match a single capture")
+	}
+	if held < 1 || held > len(sectors) {
+		return TEAM_NONE, -1, errors.New("score exceeds the amount of sectors")
+	}
+
+	// by default the allies start left/top, so they hold the sectors from the start of the list
+	fromStart := (team == TEAM_ALLIES) != l.MapIdentifier.Map().MirroredFactions
+	if fromStart {
+		return team, held - 1, nil
+	}
+	return team, len(sectors) - held, nil
 }
 
 var sectorsMap = map[SectorsIdentifier][]Sector{
@@ -0,0 +1,237 @@
+package hll
+
+import (
+	"math"
+	"slices"
+)
+
+// the default edge length of a bucket in cm
+const DefaultSpatialCellSize = 5000.0
+
+type spatialCell struct {
+	X int
+	Y int
+}
+
+// buckets the spawned players on a planar grid to answer proximity queries without comparing every pair
+type SpatialIndex struct {
+	cellSize float64
+	players  []DetailedPlayerInfo
+	byID     map[string]int
+	cells    map[spatialCell][]int
+	min      spatialCell
+	max      spatialCell
+}
+
+// players that are not spawned are left out, a cell size that is not positive falls back to the default
+func NewSpatialIndex(players []DetailedPlayerInfo, cellSize float64) *SpatialIndex {
+	if cellSize <= 0 {
+		cellSize = DefaultSpatialCellSize
+	}
+	si := &SpatialIndex{
+		cellSize: cellSize,
+		players:  []DetailedPlayerInfo{},
+		byID:     make(map[string]int),
+		cells:    make(map[spatialCell][]int),
+	}
+	for _, player := range players {
+		if !player.IsSpawned() {
+			continue
+		}
+		index := len(si.players)
+		si.players = append(si.players, player)
+		si.byID[player.ID] = index
+
+		cell := si.cellOf(player.Position)
+		if index == 0 {
+			si.min, si.max = cell, cell
+		}
+		si.min = spatialCell{X: min(si.min.X, cell.X), Y: min(si.min.Y, cell.Y)}
+		si.max = spatialCell{X: max(si.max.X, cell.X), Y: max(si.max.Y, cell.Y)}
+		si.cells[cell] = append(si.cells[cell], index)
+	}
+	return si
+}
+
+// the amount of indexed players
+func (si *SpatialIndex) Len() int {
+	return len(si.players)
+}
+
+func (si *SpatialIndex) Player(playerID string) (DetailedPlayerInfo, bool) {
+	index, ok := si.byID[playerID]
+	if !ok {
+		return DetailedPlayerInfo{}, false
+	}
+	return si.players[index], true
+}
+
+// the players within the planar radius in cm around the position, nearest first
+func (si *SpatialIndex) WithinRadius(center Position, radius float64) []DetailedPlayerInfo {
+	return si.withinRadius(center, radius, func(DetailedPlayerInfo) bool { return true })
+}
+
+// the other players within the planar radius in cm around the player, nearest first
+func (si *SpatialIndex) NearPlayer(playerID string, radius float64) []DetailedPlayerInfo {
+	player, ok := si.Player(playerID)
+	if !ok {
+		return []DetailedPlayerInfo{}
+	}
+	return si.withinRadius(player.Position, radius, func(other DetailedPlayerInfo) bool {
+		return other.ID != playerID
+	})
+}
+
+// the enemies within the planar radius in cm around the player, nearest first
+func (si *SpatialIndex) EnemiesNear(playerID string, radius float64) []DetailedPlayerInfo {
+	player, ok := si.Player(playerID)
+	if !ok {
+		return []DetailedPlayerInfo{}
+	}
+	return si.withinRadius(player.Position, radius, func(other DetailedPlayerInfo) bool {
+		return isEnemy(player, other)
+	})
+}
+
+// up to count enemies of the player, nearest first
+func (si *SpatialIndex) NearestEnemies(playerID string, count int) []DetailedPlayerInfo {
+	player, ok := si.Player(playerID)
+	if !ok || count <= 0 {
+		return []DetailedPlayerInfo{}
+	}
+
+	// search the rings of cells around the player until the found enemies are guaranteed to be the nearest ones
+	center := si.cellOf(player.Position)
+	maxRing := max(center.X-si.min.X, si.max.X-center.X, center.Y-si.min.Y, si.max.Y-center.Y)
+	found := []DetailedPlayerInfo{}
+	for ring := 0; ring <= maxRing; ring++ {
+		for x := center.X - ring; x <= center.X+ring; x++ {
+			for y := center.Y - ring; y <= center.Y+ring; y++ {
+				if max(abs(x-center.X), abs(y-center.Y)) != ring {
+					continue
+				}
+				for _, index := range si.cells[spatialCell{X: x, Y: y}] {
+					if isEnemy(player, si.players[index]) {
+						found = append(found, si.players[index])
+					}
+				}
+			}
+		}
+		sortByDistance(found, player.Position)
+		// every position outside of the searched rings is at least this far away
+		if len(found) >= count && planarDistance(player.Position, found[count-1].Position) <= float64(ring)*si.cellSize {
+			break
+		}
+	}
+	sortByDistance(found, player.Position)
+	return found[:min(count, len(found))]
+}
+
+// the players inside the polygon given by its corners, only X and Y are considered
+func (si *SpatialIndex) InPolygon(polygon []Position) []DetailedPlayerInfo {
+	if len(polygon) < 3 {
+		return []DetailedPlayerInfo{}
+	}
+	minX, minY := polygon[0].X, polygon[0].Y
+	maxX, maxY := minX, minY
+	for _, corner := range polygon[1:] {
+		minX, minY = min(minX, corner.X), min(minY, corner.Y)
+		maxX, maxY = max(maxX, corner.X), max(maxY, corner.Y)
+	}
+	return si.inBounds(Position{X: minX, Y: minY}, Position{X: maxX, Y: maxY}, func(pos Position) bool {
+		return insidePolygon(pos, polygon)
+	})
+}
+
+// the players inside the grid squares of the sector on the layer
+func (si *SpatialIndex) InSector(layer Layer, sector Sector) []DetailedPlayerInfo {
+	minX, minY := layer.Grid.GridToWorldMin(sector.From)
+	maxX, maxY := layer.Grid.GridToWorldMax(sector.To)
+	return si.inBounds(Position{X: minX, Y: minY}, Position{X: maxX, Y: maxY}, func(pos Position) bool {
+		return pos.X >= minX && pos.X < maxX && pos.Y >= minY && pos.Y < maxY
+	})
+}
+
+func (si *SpatialIndex) withinRadius(center Position, radius float64, accept func(DetailedPlayerInfo) bool) []DetailedPlayerInfo {
+	players := si.inBounds(
+		Position{X: center.X - radius, Y: center.Y - radius},
+		Position{X: center.X + radius, Y: center.Y + radius},
+		func(pos Position) bool { return planarDistance(center, pos) <= radius },
+	)
+	players = slices.DeleteFunc(players, func(player DetailedPlayerInfo) bool { return !accept(player) })
+	sortByDistance(players, center)
+	return players
+}
+
+// visits only the cells overlapping the bounds
+func (si *SpatialIndex) inBounds(minPos, maxPos Position, inside func(Position) bool) []DetailedPlayerInfo {
+	players := []DetailedPlayerInfo{}
+	if len(si.players) == 0 {
+		return players
+	}
+	from, to := si.cellOf(minPos), si.cellOf(maxPos)
+	from = spatialCell{X: max(from.X, si.min.X), Y: max(from.Y, si.min.Y)}
+	to = spatialCell{X: min(to.X, si.max.X), Y: min(to.Y, si.max.Y)}
+	for x := from.X; x <= to.X; x++ {
+		for y := from.Y; y <= to.Y; y++ {
+			for _, index := range si.cells[spatialCell{X: x, Y: y}] {
+				if inside(si.players[index].Position) {
+					players = append(players, si.players[index])
+				}
+			}
+		}
+	}
+	return players
+}
+
+func (si *SpatialIndex) cellOf(pos Position) spatialCell {
+	return spatialCell{
+		X: int(math.Floor(pos.X / si.cellSize)),
+		Y: int(math.Floor(pos.Y / si.cellSize)),
+	}
+}
+
+func isEnemy(player DetailedPlayerInfo, other DetailedPlayerInfo) bool {
+	return other.Team != TEAM_NONE && player.Team != TEAM_NONE && other.Team != player.Team
+}
+
+func planarDistance(a, b Position) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
+
+// nearest first, ties are ordered by the ID to keep the results stable
+func sortByDistance(players []DetailedPlayerInfo, center Position) {
+	slices.SortStableFunc(players, func(a, b DetailedPlayerInfo) int {
+		da, db := planarDistance(center, a.Position), planarDistance(center, b.Position)
+		switch {
+		case da < db:
+			return -1
+		case da > db:
+			return 1
+		case a.ID < b.ID:
+			return -1
+		case a.ID > b.ID:
+			return 1
+		}
+		return 0
+	})
+}
+
+// ray casting, points on the edge may fall either way
+func insidePolygon(pos Position, polygon []Position) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > pos.Y) != (b.Y > pos.Y) && pos.X < (b.X-a.X)*(pos.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
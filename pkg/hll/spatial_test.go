@@ -0,0 +1,106 @@
+package hll
+
+import (
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestSpatialIndex(t *testing.T) {
+	player := func(id string, team TeamIdentifier, x, y float64) DetailedPlayerInfo {
+		return DetailedPlayerInfo{PlayerInfo: PlayerInfo{Name: id, ID: id}, Team: team, Position: Position{X: x, Y: y, Z: 100}}
+	}
+	idsOf := func(players []DetailedPlayerInfo) []string {
+		ids := []string{}
+		for _, player := range players {
+			ids = append(ids, player.ID)
+		}
+		return ids
+	}
+
+	players := []DetailedPlayerInfo{
+		player("1", TEAM_ALLIES, 0, 0),
+		player("2", TEAM_ALLIES, 3000, 0),
+		player("3", TEAM_AXIS, 0, 4000),
+		player("4", TEAM_AXIS, -12000, -12000),
+		player("5", TEAM_AXIS, 30000, 30000),
+		{PlayerInfo: PlayerInfo{Name: "6", ID: "6"}, Team: TEAM_AXIS}, // dead
+	}
+	index := NewSpatialIndex(players, 5000)
+
+	t.Run("Dead players should not be indexed", func(t *testing.T) {
+		if index.Len() != 5 {
+			t.Errorf("Expected 5 players, but got %d", index.Len())
+		}
+		if _, ok := index.Player("6"); ok {
+			t.Error("Expected player 6 to be missing")
+		}
+	})
+
+	t.Run("Radius queries should be ordered by distance", func(t *testing.T) {
+		if ids := idsOf(index.WithinRadius(Position{}, 5000)); !slices.Equal(ids, []string{"1", "2", "3"}) {
+			t.Errorf("Expected [1 2 3], but got %v", ids)
+		}
+		if ids := idsOf(index.NearPlayer("1", 3500)); !slices.Equal(ids, []string{"2"}) {
+			t.Errorf("Expected [2], but got %v", ids)
+		}
+		if ids := idsOf(index.EnemiesNear("1", 5000)); !slices.Equal(ids, []string{"3"}) {
+			t.Errorf("Expected [3], but got %v", ids)
+		}
+	})
+
+	t.Run("Nearest enemies should search beyond the neighbouring cells", func(t *testing.T) {
+		if ids := idsOf(index.NearestEnemies("2", 2)); !slices.Equal(ids, []string{"3", "4"}) {
+			t.Errorf("Expected [3 4], but got %v", ids)
+		}
+		if ids := idsOf(index.NearestEnemies("2", 10)); !slices.Equal(ids, []string{"3", "4", "5"}) {
+			t.Errorf("Expected [3 4 5], but got %v", ids)
+		}
+		if ids := idsOf(index.NearestEnemies("3", 1)); !slices.Equal(ids, []string{"1"}) {
+			t.Errorf("Expected [1], but got %v", ids)
+		}
+	})
+
+	t.Run("Nearest enemies should match a full scan", func(t *testing.T) {
+		random := rand.New(rand.NewSource(1))
+		players := []DetailedPlayerInfo{}
+		for i := range 100 {
+			team := TEAM_ALLIES
+			if i%2 == 1 {
+				team = TEAM_AXIS
+			}
+			players = append(players, player(strconv.Itoa(i), team, random.Float64()*200000-100000, random.Float64()*200000-100000))
+		}
+		index := NewSpatialIndex(players, 5000)
+		for _, target := range players[:10] {
+			expected := []DetailedPlayerInfo{}
+			for _, other := range players {
+				if other.Team != target.Team {
+					expected = append(expected, other)
+				}
+			}
+			sortByDistance(expected, target.Position)
+			if ids := idsOf(index.NearestEnemies(target.ID, 5)); !slices.Equal(ids, idsOf(expected[:5])) {
+				t.Errorf("Expected %v, but got %v", idsOf(expected[:5]), ids)
+			}
+		}
+	})
+
+	t.Run("Polygon and sector queries", func(t *testing.T) {
+		triangle := []Position{{X: -1000, Y: -1000}, {X: 5000, Y: -1000}, {X: -1000, Y: 5000}}
+		if ids := idsOf(index.InPolygon(triangle)); !slices.Equal(ids, []string{"1", "2"}) {
+			t.Errorf("Expected [1 2], but got %v", ids)
+		}
+
+		layer := LAYER_CARENTAN_WARFARE.Layer()
+		sectors, err := layer.OrderedSectors()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the players around the center of the map are in the middle sector
+		if ids := idsOf(index.InSector(layer, sectors[2])); !slices.Contains(ids, "1") || slices.Contains(ids, "5") {
+			t.Errorf("Expected player 1 but not 5 in the middle sector, but got %v", ids)
+		}
+	})
+}
@@ -130,10 +130,10 @@ func (tv *TeamView) String() string {
 }
 
 type SquadView struct {
-	Team      TeamIdentifier
-	SquadType SquadType
-	Name      string
-	Players   []DetailedPlayerInfo
+	Team      TeamIdentifier       `json:"team"`
+	SquadType SquadType            `json:"squadType"`
+	Name      string               `json:"name"`
+	Players   []DetailedPlayerInfo `json:"players"`
 }
 
 func (sv *SquadView) PlayerCount() int {
@@ -174,6 +174,15 @@ func (sv *SquadView) HasSquadLead() bool {
 	return false
 }
 
+func (sv *SquadView) Leader() (DetailedPlayerInfo, bool) {
+	for _, player := range sv.Players {
+		if player.IsSquadLeader() {
+			return player, true
+		}
+	}
+	return EmptyDetailedPlayerInfo(), false
+}
+
 func (sv *SquadView) HasPlayer(playerID string) bool {
 	for _, player := range sv.Players {
 		if player.ID == playerID {
@@ -317,6 +326,83 @@ func PlayersToServerView(players []DetailedPlayerInfo) *ServerView {
 	return &sv
 }
 
+type SquadLeaderChange struct {
+	Squad     SquadView
+	OldLeader DetailedPlayerInfo // empty if the squad had no leader before
+	NewLeader DetailedPlayerInfo // empty if the squad is now leaderless
+}
+
+type CommanderChange struct {
+	Team         TeamIdentifier
+	OldCommander DetailedPlayerInfo // empty if the team had no commander before
+	NewCommander DetailedPlayerInfo // empty if the team has no commander anymore
+}
+
+type ServerViewDiff struct {
+	CreatedSquads    []SquadView
+	DisbandedSquads  []SquadView
+	LeaderChanges    []SquadLeaderChange
+	CommanderChanges []CommanderChange
+}
+
+func (svd ServerViewDiff) IsEmpty() bool {
+	return len(svd.CreatedSquads) == 0 && len(svd.DisbandedSquads) == 0 &&
+		len(svd.LeaderChanges) == 0 && len(svd.CommanderChanges) == 0
+}
+
+// squads are identified by their team and name, the results are ordered by team and squad name
+func DiffServerViews(oldView *ServerView, newView *ServerView) ServerViewDiff {
+	diff := ServerViewDiff{}
+	diffTeamViews(TEAM_ALLIES, oldView.Allies, newView.Allies, &diff)
+	diffTeamViews(TEAM_AXIS, oldView.Axis, newView.Axis, &diff)
+	return diff
+}
+
+func diffTeamViews(team TeamIdentifier, oldTeam *TeamView, newTeam *TeamView, diff *ServerViewDiff) {
+	if oldTeam.Commander.ID != newTeam.Commander.ID {
+		diff.CommanderChanges = append(diff.CommanderChanges, CommanderChange{
+			Team:         team,
+			OldCommander: oldTeam.Commander,
+			NewCommander: newTeam.Commander,
+		})
+	}
+
+	// players without a squad are grouped under an empty name, that is no squad which can be created or disbanded
+	squadNames := []string{}
+	for name := range oldTeam.Squads {
+		if name != "" {
+			squadNames = append(squadNames, name)
+		}
+	}
+	for name := range newTeam.Squads {
+		if _, ok := oldTeam.Squads[name]; !ok && name != "" {
+			squadNames = append(squadNames, name)
+		}
+	}
+	slices.Sort(squadNames)
+
+	for _, name := range squadNames {
+		oldSquad, existed := oldTeam.Squads[name]
+		newSquad, exists := newTeam.Squads[name]
+		switch {
+		case !existed:
+			diff.CreatedSquads = append(diff.CreatedSquads, *newSquad)
+		case !exists:
+			diff.DisbandedSquads = append(diff.DisbandedSquads, *oldSquad)
+		default:
+			oldLeader, _ := oldSquad.Leader()
+			newLeader, _ := newSquad.Leader()
+			if oldLeader.ID != newLeader.ID {
+				diff.LeaderChanges = append(diff.LeaderChanges, SquadLeaderChange{
+					Squad:     *newSquad,
+					OldLeader: oldLeader,
+					NewLeader: newLeader,
+				})
+			}
+		}
+	}
+}
+
 func guessSquadType(players []DetailedPlayerInfo) SquadType {
 	for _, player := range players {
 		if player.Role == ROLE_TANKCOMMANDER || player.Role == ROLE_CREWMAN {
@@ -1,9 +1,11 @@
 package hll
 
 import (
+	"cmp"
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/zMoooooritz/go-let-loose/pkg/logger"
 )
@@ -91,19 +93,19 @@ const (
 )
 
 type VehicleSeat struct {
-	Index         int
-	Type          VehicleSeatType
-	Weapons       []WeaponIdentifier
-	RequiresRoles []RoleIdentifier
-	Exposed       bool
+	Index         int                `json:"index"`
+	Type          VehicleSeatType    `json:"type"`
+	Weapons       []WeaponIdentifier `json:"weapons"`
+	RequiresRoles []RoleIdentifier   `json:"requiresRoles"`
+	Exposed       bool               `json:"exposed"`
 }
 
 type Vehicle struct {
-	ID       VehicleIdentifier
-	Name     string
-	Factions []FactionIdentifier
-	Type     VehicleType
-	Seats    []VehicleSeat
+	ID       VehicleIdentifier   `json:"id"`
+	Name     string              `json:"name"`
+	Factions []FactionIdentifier `json:"factions"`
+	Type     VehicleType         `json:"type"`
+	Seats    []VehicleSeat       `json:"seats"`
 }
 
 var vehicleMap = map[VehicleIdentifier]Vehicle{
@@ -1899,3 +1901,102 @@ func VehiclesByType(vehicleType VehicleType) []Vehicle {
 	}
 	return vehicles
 }
+
+func (v Vehicle) HasWeapon(weapon WeaponIdentifier) bool {
+	for _, seat := range v.Seats {
+		if slices.Contains(seat.Weapons, weapon) {
+			return true
+		}
+	}
+	return false
+}
+
+// whether one of the seats can only be taken by the role
+func (v Vehicle) RequiresRole(role RoleIdentifier) bool {
+	for _, seat := range v.Seats {
+		if slices.Contains(seat.RequiresRoles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// the vehicles of the faction that could be crewed by the roles, best match first
+// armor roles have to be required by a seat and every weapon used from one of the seats raises the score,
+// vehicles without any evidence are left out
+func MatchVehicles(faction FactionIdentifier, roles []RoleIdentifier, weapons []WeaponIdentifier) []Vehicle {
+	type candidate struct {
+		vehicle Vehicle
+		score   int
+	}
+
+	candidates := []candidate{}
+	for _, vehicle := range VehiclesByFaction(faction) {
+		if len(roles) > len(vehicle.Seats) {
+			continue
+		}
+		score := 0
+		fits := true
+		for _, role := range roles {
+			if role != ROLE_TANKCOMMANDER && role != ROLE_CREWMAN {
+				continue
+			}
+			if !vehicle.RequiresRole(role) {
+				fits = false
+				break
+			}
+			score++
+		}
+		for _, weapon := range weapons {
+			if vehicle.HasWeapon(weapon) {
+				score += 2
+			}
+		}
+		if fits && score > 0 {
+			candidates = append(candidates, candidate{vehicle: vehicle, score: score})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.vehicle.ID), string(b.vehicle.ID))
+	})
+
+	vehicles := []Vehicle{}
+	for _, c := range candidates {
+		vehicles = append(vehicles, c.vehicle)
+	}
+	return vehicles
+}
+
+// a group of co-located players of one team that probably share a vehicle
+type VehicleCrew struct {
+	ID          int                  `json:"id"` // unique while the process runs
+	Team        TeamIdentifier       `json:"team"`
+	Faction     FactionIdentifier    `json:"faction"`
+	Members     []DetailedPlayerInfo `json:"members"`     // the armor crewmen and vehicle weapon killers, passengers are not told apart from infantry
+	Candidates  []Vehicle            `json:"candidates"`  // the vehicles the crew probably uses, best match first
+	Weapons     []WeaponIdentifier   `json:"weapons"`     // the vehicle weapons the members recently killed with
+	Position    Position             `json:"position"`    // the center of the crew as of the latest poll
+	Origin      Position             `json:"origin"`      // the center of the crew when it formed
+	MaxDistance int                  `json:"maxDistance"` // the farthest planar distance in cm the crew got from its origin
+	Formed      time.Time            `json:"formed"`
+	LastSeen    time.Time            `json:"lastSeen"`
+}
+
+// the most likely vehicle of the crew
+func (vc VehicleCrew) Vehicle() (Vehicle, bool) {
+	if len(vc.Candidates) == 0 {
+		return Vehicle{}, false
+	}
+	return vc.Candidates[0], true
+}
+
+func (vc VehicleCrew) Duration() time.Duration {
+	return vc.LastSeen.Sub(vc.Formed)
+}
+
+func (vc VehicleCrew) HasMember(playerID string) bool {
+	return slices.ContainsFunc(vc.Members, func(member DetailedPlayerInfo) bool { return member.ID == playerID })
+}
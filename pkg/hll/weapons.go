@@ -282,11 +282,11 @@ const (
 )
 
 type Weapon struct {
-	ID            WeaponIdentifier
-	Name          string
-	Type          WeaponType
-	Factions      []FactionIdentifier
-	Magnification int
+	ID            WeaponIdentifier    `json:"id"`
+	Name          string              `json:"name"`
+	Type          WeaponType          `json:"type"`
+	Factions      []FactionIdentifier `json:"factions"`
+	Magnification int                 `json:"magnification"`
 }
 
 var weaponMap = map[WeaponIdentifier]Weapon{
@@ -0,0 +1,406 @@
+package rcon
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+	"github.com/zMoooooritz/go-let-loose/pkg/logger"
+)
+
+// the permissions are not fetched again for this long after fetching them failed
+const permissionRetryDelay = 10 * time.Second
+
+type PermissionLevel int
+
+const (
+	PERMISSION_EVERYONE PermissionLevel = iota
+	PERMISSION_VIP
+	PERMISSION_SPECTATOR
+	PERMISSION_JUNIOR
+	PERMISSION_SENIOR
+	PERMISSION_OWNER
+)
+
+func (pl PermissionLevel) String() string {
+	switch pl {
+	case PERMISSION_VIP:
+		return "VIP"
+	case PERMISSION_SPECTATOR:
+		return string(hll.ADMIN_ROLE_SPECTATOR)
+	case PERMISSION_JUNIOR:
+		return string(hll.ADMIN_ROLE_JUNIOR)
+	case PERMISSION_SENIOR:
+		return string(hll.ADMIN_ROLE_SENIOR)
+	case PERMISSION_OWNER:
+		return string(hll.ADMIN_ROLE_OWNER)
+	default:
+		return "everyone"
+	}
+}
+
+func PermissionFromAdminRole(role hll.AdminRole) PermissionLevel {
+	switch role {
+	case hll.ADMIN_ROLE_OWNER:
+		return PERMISSION_OWNER
+	case hll.ADMIN_ROLE_SENIOR:
+		return PERMISSION_SENIOR
+	case hll.ADMIN_ROLE_JUNIOR:
+		return PERMISSION_JUNIOR
+	case hll.ADMIN_ROLE_SPECTATOR:
+		return PERMISSION_SPECTATOR
+	default:
+		return PERMISSION_EVERYONE
+	}
+}
+
+type Command struct {
+	Name        string
+	Aliases     []string
+	Usage       string // the arguments shown by !help, e.g. "<name> <reason>"
+	Description string
+	MinArgs     int
+	Permission  PermissionLevel
+	Cooldown    time.Duration // per player, admins are not affected
+	Handler     func(CommandContext) error
+}
+
+type CommandContext struct {
+	Event      hll.ChatEvent
+	Player     hll.PlayerInfo
+	Command    string // the name or alias the command was invoked with
+	Args       []string
+	Permission PermissionLevel
+	router     *CommandRouter
+}
+
+// the arguments starting with the given index joined by spaces, e.g. for a free text reason
+func (cc CommandContext) ArgsFrom(index int) string {
+	if index >= len(cc.Args) {
+		return ""
+	}
+	return strings.Join(cc.Args[index:], " ")
+}
+
+func (cc CommandContext) Reply(format string, args ...any) error {
+	return cc.router.backend.MessagePlayer(cc.Player.ID, fmt.Sprintf(format, args...))
+}
+
+type CommandRouterConfig struct {
+	Prefix        string        // commands start with this prefix, "!" if empty
+	PermissionTTL time.Duration // admins and VIPs are fetched again after this duration, one minute if not positive
+}
+
+// the subset of the rcon the router depends on
+type commandBackend interface {
+	MessagePlayer(playerID string, message string) error
+	GetAdmins() ([]hll.Admin, error)
+	GetVIPs() ([]hll.PlayerInfo, error)
+}
+
+type CommandRouter struct {
+	config   CommandRouterConfig
+	backend  commandBackend
+	commands []*Command
+	lookup   map[string]*Command
+
+	permissions        map[string]PermissionLevel
+	permissionsFetched time.Time
+	permissionsFailed  time.Time
+	fetching           bool
+	fetched            *sync.Cond // signals the end of a fetch to the callers waiting for the first permissions
+	lastUsage          map[string]time.Time
+	mutex              sync.Mutex
+}
+
+// the router handles all chat messages starting with the prefix, it comes with a built-in help command
+func NewCommandRouter(rcn *Rcon, cfg CommandRouterConfig) (*CommandRouter, error) {
+	if !rcn.Events.enabled {
+		return nil, errEventsDisabled
+	}
+	router := newCommandRouter(rcn, cfg)
+	rcn.OnChat(func(event hll.ChatEvent) {
+		// commands talk to the server, so they must not hold up the delivery of other events
+		go router.Handle(event)
+	})
+	return router, nil
+}
+
+func newCommandRouter(backend commandBackend, cfg CommandRouterConfig) *CommandRouter {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "!"
+	}
+	if cfg.PermissionTTL <= 0 {
+		cfg.PermissionTTL = time.Minute
+	}
+
+	router := &CommandRouter{
+		config:    cfg,
+		backend:   backend,
+		lookup:    make(map[string]*Command),
+		lastUsage: make(map[string]time.Time),
+	}
+	router.fetched = sync.NewCond(&router.mutex)
+	router.Register(Command{
+		Name:        "help",
+		Aliases:     []string{"commands"},
+		Usage:       "[command]",
+		Description: "lists the available commands",
+		Handler:     router.help,
+	})
+	return router
+}
+
+func (cr *CommandRouter) Register(cmd Command) error {
+	if cmd.Name == "" || cmd.Handler == nil {
+		return errors.New("a command requires a name and a handler")
+	}
+
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	names := append([]string{cmd.Name}, cmd.Aliases...)
+	for _, name := range names {
+		if _, ok := cr.lookup[strings.ToLower(name)]; ok {
+			return fmt.Errorf("the command %s is already registered", name)
+		}
+	}
+	command := &cmd
+	for _, name := range names {
+		cr.lookup[strings.ToLower(name)] = command
+	}
+	cr.commands = append(cr.commands, command)
+	return nil
+}
+
+// all registered commands ordered by name
+func (cr *CommandRouter) Commands() []Command {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	commands := []Command{}
+	for _, command := range cr.commands {
+		commands = append(commands, *command)
+	}
+	slices.SortFunc(commands, func(a, b Command) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return commands
+}
+
+func (cr *CommandRouter) Handle(event hll.ChatEvent) {
+	message := strings.TrimSpace(event.Message)
+	if !strings.HasPrefix(message, cr.config.Prefix) {
+		return
+	}
+	args := SplitCommandArgs(strings.TrimPrefix(message, cr.config.Prefix))
+	if len(args) == 0 {
+		return
+	}
+
+	cr.mutex.Lock()
+	command, ok := cr.lookup[strings.ToLower(args[0])]
+	cr.mutex.Unlock()
+
+	ctx := CommandContext{
+		Event:      event,
+		Player:     event.Player,
+		Command:    args[0],
+		Args:       args[1:],
+		Permission: cr.permission(event.Player.ID),
+		router:     cr,
+	}
+
+	if !ok {
+		cr.reply(ctx, "Unknown command %s%s, see %shelp", cr.config.Prefix, args[0], cr.config.Prefix)
+		return
+	}
+	if ctx.Permission < command.Permission {
+		cr.reply(ctx, "%s%s requires the permission %s", cr.config.Prefix, command.Name, command.Permission)
+		return
+	}
+	if len(ctx.Args) < command.MinArgs {
+		cr.reply(ctx, "Usage: %s", cr.usage(*command))
+		return
+	}
+	remaining, release := cr.cooldown(ctx, *command)
+	if remaining > 0 {
+		cr.reply(ctx, "%s%s is on cooldown for %ds", cr.config.Prefix, command.Name, int(remaining.Seconds())+1)
+		return
+	}
+
+	if err := command.Handler(ctx); err != nil {
+		release()
+		cr.reply(ctx, "%s%s failed: %s", cr.config.Prefix, command.Name, err)
+		return
+	}
+}
+
+func (cr *CommandRouter) reply(ctx CommandContext, format string, args ...any) {
+	if err := ctx.Reply(format, args...); err != nil {
+		logger.Error("replying to chat command failed", err)
+	}
+}
+
+func (cr *CommandRouter) usage(command Command) string {
+	usage := cr.config.Prefix + command.Name
+	if command.Usage != "" {
+		usage += " " + command.Usage
+	}
+	return usage
+}
+
+// returns the remaining cooldown of the command for the player, otherwise the cooldown starts right away
+// so that concurrent messages of the player cannot pass the check; release undoes it for failed commands
+func (cr *CommandRouter) cooldown(ctx CommandContext, command Command) (time.Duration, func()) {
+	if command.Cooldown <= 0 || ctx.Permission >= PERMISSION_SPECTATOR {
+		return 0, func() {}
+	}
+
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	key := command.Name + "|" + ctx.Player.ID
+	previous, used := cr.lastUsage[key]
+	if remaining := command.Cooldown - time.Since(previous); used && remaining > 0 {
+		return remaining, func() {}
+	}
+	reserved := time.Now()
+	cr.lastUsage[key] = reserved
+	return 0, func() {
+		cr.mutex.Lock()
+		defer cr.mutex.Unlock()
+
+		if !cr.lastUsage[key].Equal(reserved) {
+			return
+		}
+		if used {
+			cr.lastUsage[key] = previous
+		} else {
+			delete(cr.lastUsage, key)
+		}
+	}
+}
+
+// the server is asked outside of the lock, meanwhile and after a failure the previous permissions are used;
+// without previous permissions the callers wait for the running fetch
+func (cr *CommandRouter) permission(playerID string) PermissionLevel {
+	cr.mutex.Lock()
+	for cr.permissions == nil && cr.fetching {
+		cr.fetched.Wait()
+	}
+	expired := cr.permissions == nil || time.Since(cr.permissionsFetched) > cr.config.PermissionTTL
+	failedRecently := time.Since(cr.permissionsFailed) < permissionRetryDelay
+	fetch := expired && !failedRecently && !cr.fetching
+	if fetch {
+		cr.fetching = true
+	}
+	cr.mutex.Unlock()
+
+	if fetch {
+		permissions, err := cr.fetchPermissions()
+
+		cr.mutex.Lock()
+		cr.fetching = false
+		if err != nil {
+			logger.Error("fetching command permissions failed", err)
+			cr.permissionsFailed = time.Now()
+		} else {
+			cr.permissions = permissions
+			cr.permissionsFetched = time.Now()
+		}
+		cr.fetched.Broadcast()
+		cr.mutex.Unlock()
+	}
+
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	return cr.permissions[playerID]
+}
+
+func (cr *CommandRouter) fetchPermissions() (map[string]PermissionLevel, error) {
+	permissions := make(map[string]PermissionLevel)
+
+	vips, err := cr.backend.GetVIPs()
+	if err != nil {
+		return nil, err
+	}
+	for _, vip := range vips {
+		permissions[vip.ID] = PERMISSION_VIP
+	}
+
+	admins, err := cr.backend.GetAdmins()
+	if err != nil {
+		return nil, err
+	}
+	for _, admin := range admins {
+		permissions[admin.ID] = max(permissions[admin.ID], PermissionFromAdminRole(admin.Role))
+	}
+	return permissions, nil
+}
+
+func (cr *CommandRouter) help(ctx CommandContext) error {
+	if len(ctx.Args) > 0 {
+		cr.mutex.Lock()
+		command, ok := cr.lookup[strings.ToLower(strings.TrimPrefix(ctx.Args[0], cr.config.Prefix))]
+		cr.mutex.Unlock()
+		if !ok || ctx.Permission < command.Permission {
+			return fmt.Errorf("unknown command %s", ctx.Args[0])
+		}
+
+		lines := []string{cr.usage(*command)}
+		if command.Description != "" {
+			lines = append(lines, command.Description)
+		}
+		if len(command.Aliases) > 0 {
+			lines = append(lines, "Aliases: "+strings.Join(command.Aliases, ", "))
+		}
+		return ctx.Reply("%s", strings.Join(lines, "\n"))
+	}
+
+	lines := []string{"Available commands:"}
+	for _, command := range cr.Commands() {
+		if ctx.Permission < command.Permission {
+			continue
+		}
+		line := cr.usage(command)
+		if command.Description != "" {
+			line += " - " + command.Description
+		}
+		lines = append(lines, line)
+	}
+	return ctx.Reply("%s", strings.Join(lines, "\n"))
+}
+
+// splits the text at whitespace, double quotes group words into a single argument
+func SplitCommandArgs(text string) []string {
+	args := []string{}
+	var current strings.Builder
+	inQuotes, hasArg := false, false
+
+	for _, r := range text {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			hasArg = true
+		case unicode.IsSpace(r) && !inQuotes:
+			if hasArg {
+				args = append(args, current.String())
+				current.Reset()
+				hasArg = false
+			}
+		default:
+			current.WriteRune(r)
+			hasArg = true
+		}
+	}
+	if hasArg {
+		args = append(args, current.String())
+	}
+	return args
+}
@@ -0,0 +1,290 @@
+package rcon
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+type fakeBackend struct {
+	err      error         // returned when fetching the admins and VIPs
+	block    chan struct{} // holds up fetching the VIPs until closed
+	fetches  int
+	admins   []hll.Admin
+	vips     []hll.PlayerInfo
+	players  []hll.DetailedPlayerInfo
+	mutex    sync.Mutex
+	messages map[string][]string
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{messages: make(map[string][]string)}
+}
+
+func (fb *fakeBackend) MessagePlayer(playerID string, message string) error {
+	fb.mutex.Lock()
+	defer fb.mutex.Unlock()
+	fb.messages[playerID] = append(fb.messages[playerID], message)
+	return nil
+}
+
+func (fb *fakeBackend) GetAdmins() ([]hll.Admin, error) {
+	return fb.admins, fb.err
+}
+
+func (fb *fakeBackend) GetVIPs() ([]hll.PlayerInfo, error) {
+	if fb.block != nil {
+		<-fb.block
+	}
+	fb.mutex.Lock()
+	defer fb.mutex.Unlock()
+	fb.fetches++
+	return fb.vips, fb.err
+}
+
+func (fb *fakeBackend) GetPlayersInfo() ([]hll.DetailedPlayerInfo, error) {
+	return fb.players, nil
+}
+
+func (fb *fakeBackend) lastMessage(playerID string) string {
+	fb.mutex.Lock()
+	defer fb.mutex.Unlock()
+	messages := fb.messages[playerID]
+	if len(messages) == 0 {
+		return ""
+	}
+	return messages[len(messages)-1]
+}
+
+func chatFrom(player hll.PlayerInfo, message string) hll.ChatEvent {
+	return hll.ChatEvent{
+		GenericEvent: hll.GenericEvent{EventType: hll.EVENT_CHAT, EventTime: time.Now()},
+		Player:       player,
+		Message:      message,
+	}
+}
+
+func TestSplitCommandArgs(t *testing.T) {
+	tests := map[string][]string{
+		"report Bob cheating":         {"report", "Bob", "cheating"},
+		`report "Bob the Builder" tk`: {"report", "Bob the Builder", "tk"},
+		"  spaced   out  ":            {"spaced", "out"},
+		`empty ""`:                    {"empty", ""},
+		"":                            {},
+	}
+	for input, expected := range tests {
+		if args := SplitCommandArgs(input); !reflect.DeepEqual(args, expected) {
+			t.Errorf("Expected %q for %q, but got %q", expected, input, args)
+		}
+	}
+}
+
+func TestCommandRouter(t *testing.T) {
+	player := hll.PlayerInfo{Name: "Player", ID: "1"}
+	vip := hll.PlayerInfo{Name: "VIP", ID: "2"}
+	admin := hll.PlayerInfo{Name: "Admin", ID: "3"}
+
+	backend := newFakeBackend()
+	backend.vips = []hll.PlayerInfo{vip, admin}
+	backend.admins = []hll.Admin{{PlayerInfo: admin, Role: hll.ADMIN_ROLE_SENIOR}}
+
+	router := newCommandRouter(backend, CommandRouterConfig{})
+
+	var greeted []string
+	err := router.Register(Command{
+		Name:        "greet",
+		Aliases:     []string{"hi"},
+		Usage:       "<name>",
+		Description: "greets someone",
+		MinArgs:     1,
+		Cooldown:    time.Hour,
+		Handler: func(ctx CommandContext) error {
+			greeted = append(greeted, ctx.ArgsFrom(0))
+			return ctx.Reply("Hello %s", ctx.Args[0])
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.Register(Command{
+		Name:       "kickall",
+		Permission: PERMISSION_SENIOR,
+		Handler:    func(ctx CommandContext) error { return errors.New("not today") },
+	})
+
+	t.Run("duplicate registration", func(t *testing.T) {
+		if err := router.Register(Command{Name: "other", Aliases: []string{"HI"}, Handler: func(CommandContext) error { return nil }}); err == nil {
+			t.Error("Expected an error for a duplicate alias, but got none")
+		}
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		levels := map[string]PermissionLevel{player.ID: PERMISSION_EVERYONE, vip.ID: PERMISSION_VIP, admin.ID: PERMISSION_SENIOR}
+		for id, expected := range levels {
+			if level := router.permission(id); level != expected {
+				t.Errorf("Expected permission %s for %s, but got %s", expected, id, level)
+			}
+		}
+	})
+
+	t.Run("alias, arguments and cooldown", func(t *testing.T) {
+		router.Handle(chatFrom(player, `!HI "Big Bob"`))
+		if backend.lastMessage(player.ID) != "Hello Big Bob" {
+			t.Errorf("Expected a greeting, but got %q", backend.lastMessage(player.ID))
+		}
+
+		router.Handle(chatFrom(player, "!greet Bob"))
+		if !strings.Contains(backend.lastMessage(player.ID), "cooldown") || len(greeted) != 1 {
+			t.Errorf("Expected a cooldown reply, but got %q", backend.lastMessage(player.ID))
+		}
+
+		router.Handle(chatFrom(admin, "!greet Bob"))
+		router.Handle(chatFrom(admin, "!greet Bob"))
+		if len(greeted) != 3 {
+			t.Errorf("Expected admins to bypass the cooldown, but got %d greetings", len(greeted))
+		}
+	})
+
+	t.Run("usage and permission replies", func(t *testing.T) {
+		router.Handle(chatFrom(vip, "!greet"))
+		if backend.lastMessage(vip.ID) != "Usage: !greet <name>" {
+			t.Errorf("Expected the usage, but got %q", backend.lastMessage(vip.ID))
+		}
+
+		router.Handle(chatFrom(vip, "!kickall"))
+		if !strings.Contains(backend.lastMessage(vip.ID), "requires the permission senior") {
+			t.Errorf("Expected a permission reply, but got %q", backend.lastMessage(vip.ID))
+		}
+
+		router.Handle(chatFrom(admin, "!kickall"))
+		if backend.lastMessage(admin.ID) != "!kickall failed: not today" {
+			t.Errorf("Expected the error as reply, but got %q", backend.lastMessage(admin.ID))
+		}
+	})
+
+	t.Run("regular chat is ignored", func(t *testing.T) {
+		before := len(backend.messages[player.ID])
+		router.Handle(chatFrom(player, "gg wp"))
+		if len(backend.messages[player.ID]) != before {
+			t.Error("Expected no reply to regular chat")
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		router.Handle(chatFrom(player, "!help"))
+		help := backend.lastMessage(player.ID)
+		if !strings.Contains(help, "!greet <name> - greets someone") || strings.Contains(help, "kickall") {
+			t.Errorf("Expected only the permitted commands, but got %q", help)
+		}
+
+		router.Handle(chatFrom(admin, "!help"))
+		if !strings.Contains(backend.lastMessage(admin.ID), "!kickall") {
+			t.Errorf("Expected admin commands for admins, but got %q", backend.lastMessage(admin.ID))
+		}
+
+		router.Handle(chatFrom(player, "!help hi"))
+		if !strings.Contains(backend.lastMessage(player.ID), "Aliases: hi") {
+			t.Errorf("Expected the details of the command, but got %q", backend.lastMessage(player.ID))
+		}
+	})
+}
+
+func TestCommandRouterFailures(t *testing.T) {
+	player := hll.PlayerInfo{Name: "Player", ID: "1"}
+
+	t.Run("failed commands do not start the cooldown", func(t *testing.T) {
+		router := newCommandRouter(newFakeBackend(), CommandRouterConfig{})
+		calls := 0
+		router.Register(Command{
+			Name:     "flaky",
+			Cooldown: time.Hour,
+			Handler: func(ctx CommandContext) error {
+				calls++
+				if calls == 1 {
+					return errors.New("server busy")
+				}
+				return nil
+			},
+		})
+
+		router.Handle(chatFrom(player, "!flaky"))
+		router.Handle(chatFrom(player, "!flaky"))
+		router.Handle(chatFrom(player, "!flaky"))
+		if calls != 2 {
+			t.Errorf("Expected the retry to run and the third call to be on cooldown, but got %d calls", calls)
+		}
+	})
+
+	t.Run("failed permission fetches are not repeated right away", func(t *testing.T) {
+		backend := newFakeBackend()
+		backend.err = errors.New("connection lost")
+		router := newCommandRouter(backend, CommandRouterConfig{})
+
+		for range 3 {
+			if level := router.permission(player.ID); level != PERMISSION_EVERYONE {
+				t.Errorf("Expected everyone, but got %s", level)
+			}
+		}
+		if backend.fetches != 1 {
+			t.Errorf("Expected a single fetch, but got %d", backend.fetches)
+		}
+	})
+	t.Run("concurrent messages cannot bypass the cooldown", func(t *testing.T) {
+		router := newCommandRouter(newFakeBackend(), CommandRouterConfig{})
+		var calls atomic.Int32
+		release := make(chan struct{})
+		router.Register(Command{
+			Name:     "report",
+			Cooldown: time.Hour,
+			Handler: func(ctx CommandContext) error {
+				calls.Add(1)
+				<-release
+				return nil
+			},
+		})
+
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				router.Handle(chatFrom(player, "!report"))
+			}()
+		}
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		if calls.Load() != 1 {
+			t.Errorf("Expected a single call, but got %d", calls.Load())
+		}
+	})
+
+	t.Run("callers wait for the first permission fetch", func(t *testing.T) {
+		admin := hll.PlayerInfo{Name: "Admin", ID: "3"}
+		backend := newFakeBackend()
+		backend.admins = []hll.Admin{{PlayerInfo: admin, Role: hll.ADMIN_ROLE_SENIOR}}
+		backend.block = make(chan struct{})
+		router := newCommandRouter(backend, CommandRouterConfig{})
+
+		levels := make(chan PermissionLevel, 3)
+		for range 3 {
+			go func() { levels <- router.permission(admin.ID) }()
+		}
+		time.Sleep(50 * time.Millisecond)
+		close(backend.block)
+		for range 3 {
+			if level := <-levels; level != PERMISSION_SENIOR {
+				t.Errorf("Expected senior, but got %s", level)
+			}
+		}
+		if backend.fetches != 1 {
+			t.Errorf("Expected a single fetch, but got %d", backend.fetches)
+		}
+	})
+}
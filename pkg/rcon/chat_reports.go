@@ -0,0 +1,349 @@
+package rcon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+	"github.com/zMoooooritz/go-let-loose/pkg/logger"
+)
+
+type Report struct {
+	ID         int
+	Reported   hll.PlayerInfo
+	Reporters  []hll.PlayerInfo // the first reporter created the report, later ones were merged into it
+	Reason     string           // the reasons of merged reports are appended
+	Created    time.Time
+	Resolved   bool
+	ResolvedBy hll.PlayerInfo
+	ResolvedAt time.Time
+}
+
+func (r Report) String() string {
+	if len(r.Reporters) == 0 {
+		return fmt.Sprintf("#%d %s: %s", r.ID, r.Reported.Name, r.Reason)
+	}
+	reporters := r.Reporters[0].Name
+	if len(r.Reporters) > 1 {
+		reporters += fmt.Sprintf(" +%d", len(r.Reporters)-1)
+	}
+	return fmt.Sprintf("#%d %s: %s (by %s)", r.ID, r.Reported.Name, r.Reason, reporters)
+}
+
+type ReportConfig struct {
+	StorePath       string          // the reports are persisted as JSON in this file
+	AdminPermission PermissionLevel // admins with this permission are notified and may list and resolve reports, junior if not set
+	Cooldown        time.Duration   // per player between two reports, one minute if not positive
+}
+
+type reportBackend interface {
+	commandBackend
+	GetPlayersInfo() ([]hll.DetailedPlayerInfo, error)
+}
+
+// the chat commands !report, !reports and !resolve backed by a persisted queue
+type ReportManager struct {
+	config  ReportConfig
+	backend reportBackend
+	router  *CommandRouter
+	reports []Report
+	nextID  int
+	mutex   sync.Mutex
+}
+
+func NewReportManager(rcn *Rcon, router *CommandRouter, cfg ReportConfig) (*ReportManager, error) {
+	return newReportManager(rcn, router, cfg)
+}
+
+func newReportManager(backend reportBackend, router *CommandRouter, cfg ReportConfig) (*ReportManager, error) {
+	if cfg.StorePath == "" {
+		return nil, errors.New("the reports require a store path")
+	}
+	if cfg.AdminPermission == PERMISSION_EVERYONE {
+		cfg.AdminPermission = PERMISSION_JUNIOR
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = time.Minute
+	}
+
+	rm := &ReportManager{
+		config:  cfg,
+		backend: backend,
+		router:  router,
+		reports: []Report{},
+		nextID:  1,
+	}
+	if err := rm.load(); err != nil {
+		return nil, err
+	}
+
+	commands := []Command{
+		{
+			Name:        "report",
+			Usage:       "<name> <reason>",
+			Description: "reports a player to the admins",
+			MinArgs:     2,
+			Cooldown:    cfg.Cooldown,
+			Handler:     rm.reportCommand,
+		},
+		{
+			Name:        "reports",
+			Description: "lists the open reports",
+			Permission:  cfg.AdminPermission,
+			Handler:     rm.reportsCommand,
+		},
+		{
+			Name:        "resolve",
+			Usage:       "<id>",
+			Description: "marks a report as resolved",
+			MinArgs:     1,
+			Permission:  cfg.AdminPermission,
+			Handler:     rm.resolveCommand,
+		},
+	}
+	for _, command := range commands {
+		if err := router.Register(command); err != nil {
+			return nil, err
+		}
+	}
+	return rm, nil
+}
+
+// returns the reports ordered by their ID
+func (rm *ReportManager) Reports(includeResolved bool) []Report {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	reports := []Report{}
+	for _, report := range rm.reports {
+		if includeResolved || !report.Resolved {
+			reports = append(reports, report)
+		}
+	}
+	return reports
+}
+
+func (rm *ReportManager) Resolve(id int, admin hll.PlayerInfo) (Report, error) {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	for i, report := range rm.reports {
+		if report.ID != id {
+			continue
+		}
+		if report.Resolved {
+			return report, fmt.Errorf("report #%d was already resolved by %s", id, report.ResolvedBy.Name)
+		}
+		rm.reports[i].Resolved = true
+		rm.reports[i].ResolvedBy = admin
+		rm.reports[i].ResolvedAt = time.Now()
+		if err := rm.save(); err != nil {
+			rm.reports[i] = report
+			return report, err
+		}
+		return rm.reports[i], nil
+	}
+	return Report{}, fmt.Errorf("there is no report #%d", id)
+}
+
+// adds the report, an open report of the same player absorbs the new one; returns whether it was merged
+func (rm *ReportManager) add(reporter hll.PlayerInfo, reported hll.PlayerInfo, reason string) (Report, bool, error) {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	for i, report := range rm.reports {
+		if report.Resolved || report.Reported.ID != reported.ID {
+			continue
+		}
+		if slices.ContainsFunc(report.Reporters, func(p hll.PlayerInfo) bool { return p.ID == reporter.ID }) {
+			return report, true, fmt.Errorf("you already reported %s", reported.Name)
+		}
+		rm.reports[i].Reporters = append(slices.Clone(report.Reporters), reporter)
+		if reason != "" && !strings.Contains(report.Reason, reason) {
+			rm.reports[i].Reason = report.Reason + "; " + reason
+		}
+		if err := rm.save(); err != nil {
+			rm.reports[i] = report
+			return report, true, err
+		}
+		return rm.reports[i], true, nil
+	}
+
+	report := Report{
+		ID:        rm.nextID,
+		Reported:  reported,
+		Reporters: []hll.PlayerInfo{reporter},
+		Reason:    reason,
+		Created:   time.Now(),
+	}
+	rm.reports = append(rm.reports, report)
+	if err := rm.save(); err != nil {
+		rm.reports = rm.reports[:len(rm.reports)-1]
+		return report, false, err
+	}
+	rm.nextID++
+	return report, false, nil
+}
+
+func (rm *ReportManager) reportCommand(ctx CommandContext) error {
+	players, err := rm.backend.GetPlayersInfo()
+	if err != nil {
+		return err
+	}
+	reported, err := matchPlayerName(players, ctx.Args[0])
+	if err != nil {
+		return err
+	}
+	if reported.ID == ctx.Player.ID {
+		return errors.New("you cannot report yourself")
+	}
+
+	report, merged, err := rm.add(ctx.Player, reported.PlayerInfo, ctx.ArgsFrom(1))
+	if err != nil {
+		return err
+	}
+	if merged {
+		return ctx.Reply("Your report was added to the open report #%d on %s", report.ID, reported.Name)
+	}
+
+	rm.notifyAdmins(players, fmt.Sprintf("New report %s\nUse %sreports and %sresolve %d", report, rm.router.config.Prefix, rm.router.config.Prefix, report.ID))
+	return ctx.Reply("Thank you, %s was reported to the admins", reported.Name)
+}
+
+func (rm *ReportManager) reportsCommand(ctx CommandContext) error {
+	reports := rm.Reports(false)
+	if len(reports) == 0 {
+		return ctx.Reply("There are no open reports")
+	}
+
+	lines := []string{"Open reports:"}
+	for _, report := range reports {
+		lines = append(lines, report.String())
+	}
+	return ctx.Reply("%s", strings.Join(lines, "\n"))
+}
+
+func (rm *ReportManager) resolveCommand(ctx CommandContext) error {
+	id, err := strconv.Atoi(strings.TrimPrefix(ctx.Args[0], "#"))
+	if err != nil {
+		return fmt.Errorf("invalid report id %s", ctx.Args[0])
+	}
+	report, err := rm.Resolve(id, ctx.Player)
+	if err != nil {
+		return err
+	}
+	return ctx.Reply("Resolved report #%d on %s", report.ID, report.Reported.Name)
+}
+
+func (rm *ReportManager) notifyAdmins(players []hll.DetailedPlayerInfo, message string) {
+	for _, player := range players {
+		if rm.router.permission(player.ID) < rm.config.AdminPermission {
+			continue
+		}
+		if err := rm.backend.MessagePlayer(player.ID, message); err != nil {
+			logger.Error("notifying admin about report failed", err)
+		}
+	}
+}
+
+type reportStore struct {
+	Reports []Report
+}
+
+func (rm *ReportManager) load() error {
+	data, err := os.ReadFile(rm.config.StorePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var store reportStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		return err
+	}
+	rm.reports = store.Reports
+	for _, report := range rm.reports {
+		rm.nextID = max(rm.nextID, report.ID+1)
+	}
+	return nil
+}
+
+func (rm *ReportManager) save() error {
+	data, err := json.MarshalIndent(reportStore{Reports: rm.reports}, "", "  ")
+	if err != nil {
+		return err
+	}
+	// replacing the file keeps the store intact if writing fails halfway
+	if err := os.WriteFile(rm.config.StorePath+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(rm.config.StorePath+".tmp", rm.config.StorePath)
+}
+
+// finds the player by an exact, prefix or substring match ignoring the case,
+// a few typos are tolerated if nothing else matches
+func matchPlayerName(players []hll.DetailedPlayerInfo, query string) (hll.DetailedPlayerInfo, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return hll.DetailedPlayerInfo{}, errors.New("no player name given")
+	}
+
+	matchers := []func(name string) bool{
+		func(name string) bool { return name == query },
+		func(name string) bool { return strings.HasPrefix(name, query) },
+		func(name string) bool { return strings.Contains(name, query) },
+		func(name string) bool { return levenshtein(name, query) <= max(1, len(query)/4) },
+	}
+	for _, matches := range matchers {
+		candidates := []hll.DetailedPlayerInfo{}
+		for _, player := range players {
+			if matches(strings.ToLower(player.Name)) {
+				candidates = append(candidates, player)
+			}
+		}
+
+		switch len(candidates) {
+		case 0:
+			continue
+		case 1:
+			return candidates[0], nil
+		default:
+			names := []string{}
+			for _, candidate := range candidates {
+				names = append(names, candidate.Name)
+			}
+			return hll.DetailedPlayerInfo{}, fmt.Errorf("%s matches several players: %s", query, strings.Join(names, ", "))
+		}
+	}
+	return hll.DetailedPlayerInfo{}, fmt.Errorf("no player matches %s", query)
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
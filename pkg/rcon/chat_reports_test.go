@@ -0,0 +1,141 @@
+package rcon
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestMatchPlayerName(t *testing.T) {
+	players := []hll.DetailedPlayerInfo{
+		{PlayerInfo: hll.PlayerInfo{Name: "Sniper", ID: "1"}},
+		{PlayerInfo: hll.PlayerInfo{Name: "SniperWolf", ID: "2"}},
+		{PlayerInfo: hll.PlayerInfo{Name: "[CLAN] Tanker", ID: "3"}},
+		{PlayerInfo: hll.PlayerInfo{Name: "Medic Mike", ID: "4"}},
+	}
+
+	tests := map[string]string{
+		"sniper":     "1", // exact matches win over prefixes
+		"sniperw":    "2",
+		"tank":       "3",
+		"medik mike": "4",
+	}
+	for query, expected := range tests {
+		player, err := matchPlayerName(players, query)
+		if err != nil || player.ID != expected {
+			t.Errorf("Expected player %s for %q, but got %s (%v)", expected, query, player.ID, err)
+		}
+	}
+
+	if _, err := matchPlayerName(players, "i"); err == nil || !strings.Contains(err.Error(), "several") {
+		t.Errorf("Expected an ambiguous match, but got %v", err)
+	}
+	if _, err := matchPlayerName(players, "nobody"); err == nil {
+		t.Error("Expected no match, but got one")
+	}
+}
+
+func TestReportManager(t *testing.T) {
+	alice := hll.PlayerInfo{Name: "Alice", ID: "1"}
+	bob := hll.PlayerInfo{Name: "Bob", ID: "2"}
+	carol := hll.PlayerInfo{Name: "Carol", ID: "3"}
+	admin := hll.PlayerInfo{Name: "Admin", ID: "4"}
+
+	backend := newFakeBackend()
+	backend.admins = []hll.Admin{{PlayerInfo: admin, Role: hll.ADMIN_ROLE_JUNIOR}}
+	for _, player := range []hll.PlayerInfo{alice, bob, carol, admin} {
+		backend.players = append(backend.players, hll.DetailedPlayerInfo{PlayerInfo: player})
+	}
+
+	store := filepath.Join(t.TempDir(), "reports.json")
+	router := newCommandRouter(backend, CommandRouterConfig{})
+	reports, err := newReportManager(backend, router, ReportConfig{StorePath: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router.Handle(chatFrom(alice, "!report bo team killing at the garrison"))
+	if !strings.Contains(backend.lastMessage(alice.ID), "Bob was reported") {
+		t.Errorf("Expected a confirmation, but got %q", backend.lastMessage(alice.ID))
+	}
+	if !strings.Contains(backend.lastMessage(admin.ID), "#1 Bob: team killing at the garrison (by Alice)") {
+		t.Errorf("Expected the admin to be notified, but got %q", backend.lastMessage(admin.ID))
+	}
+
+	router.Handle(chatFrom(carol, "!report bob griefing"))
+	if !strings.Contains(backend.lastMessage(carol.ID), "open report #1") {
+		t.Errorf("Expected the report to be merged, but got %q", backend.lastMessage(carol.ID))
+	}
+
+	router.Handle(chatFrom(bob, "!reports"))
+	if !strings.Contains(backend.lastMessage(bob.ID), "requires the permission") {
+		t.Errorf("Expected players not to see the reports, but got %q", backend.lastMessage(bob.ID))
+	}
+
+	router.Handle(chatFrom(admin, "!reports"))
+	if !strings.Contains(backend.lastMessage(admin.ID), "#1 Bob: team killing at the garrison; griefing (by Alice +1)") {
+		t.Errorf("Expected the open report, but got %q", backend.lastMessage(admin.ID))
+	}
+
+	router.Handle(chatFrom(admin, "!resolve #1"))
+	if len(reports.Reports(false)) != 0 {
+		t.Errorf("Expected no open reports, but got %v", reports.Reports(false))
+	}
+
+	// the queue is restored from the store
+	restored, err := newReportManager(backend, newCommandRouter(backend, CommandRouterConfig{}), ReportConfig{StorePath: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := restored.Reports(true)
+	if len(all) != 1 || !all[0].Resolved || all[0].ResolvedBy != admin || len(all[0].Reporters) != 2 {
+		t.Errorf("Expected the resolved report to be restored, but got %+v", all)
+	}
+	if report, _, _ := restored.add(carol, bob, "again"); report.ID != 2 {
+		t.Errorf("Expected a new report with the next id, but got #%d", report.ID)
+	}
+}
+
+func TestReportManagerFailures(t *testing.T) {
+	alice := hll.PlayerInfo{Name: "Alice", ID: "1"}
+	bob := hll.PlayerInfo{Name: "Bob", ID: "2"}
+	admin := hll.PlayerInfo{Name: "Admin", ID: "4"}
+
+	backend := newFakeBackend()
+	store := filepath.Join(t.TempDir(), "reports.json")
+	reports, err := newReportManager(backend, newCommandRouter(backend, CommandRouterConfig{}), ReportConfig{StorePath: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := reports.add(alice, bob, "team killing"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("String should not require a reporter", func(t *testing.T) {
+		report := Report{ID: 7, Reported: bob, Reason: "imported"}
+		if report.String() != "#7 Bob: imported" {
+			t.Errorf("Expected the report without reporters, but got %q", report.String())
+		}
+	})
+
+	t.Run("Resolve should be rolled back if saving fails", func(t *testing.T) {
+		reports.config.StorePath = filepath.Join(t.TempDir(), "missing", "reports.json")
+		defer func() { reports.config.StorePath = store }()
+
+		if _, err := reports.Resolve(1, admin); err == nil {
+			t.Fatal("Expected an error, but got none")
+		}
+		if open := reports.Reports(false); len(open) != 1 || open[0].ResolvedBy.ID != "" {
+			t.Errorf("Expected the report to stay open, but got %+v", open)
+		}
+
+		if _, _, err := reports.add(admin, bob, "griefing"); err == nil {
+			t.Fatal("Expected an error, but got none")
+		}
+		if open := reports.Reports(false); len(open[0].Reporters) != 1 || open[0].Reason != "team killing" {
+			t.Errorf("Expected the merge to be rolled back, but got %+v", open)
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package rcon
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+const (
+	crewRadius       = 500.0 // players of a vehicle share nearly the same position
+	crewMinSize      = 2
+	crewWeaponWindow = 3 * time.Minute // kills with vehicle weapons older than this are no evidence anymore
+)
+
+// groups co-located armor crews and players recently killing with vehicle weapons into probable vehicle crews
+type crewTracker struct {
+	crews   []*hll.VehicleCrew
+	weapons map[string]map[hll.WeaponIdentifier]time.Time
+	nextID  int
+	mutex   sync.Mutex
+}
+
+func newCrewTracker() *crewTracker {
+	return &crewTracker{
+		crews:   []*hll.VehicleCrew{},
+		weapons: make(map[string]map[hll.WeaponIdentifier]time.Time),
+		nextID:  1,
+	}
+}
+
+// the crews of the latest poll ordered by their formation
+func (ct *crewTracker) current() []hll.VehicleCrew {
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
+
+	crews := []hll.VehicleCrew{}
+	for _, crew := range ct.crews {
+		crews = append(crews, *crew)
+	}
+	return crews
+}
+
+// remembers the vehicle weapons the players killed with as evidence for their vehicle,
+// the kills are timed by the client clock as the polls that expire them are
+func (ct *crewTracker) processLog(event hll.Event, now time.Time) {
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
+
+	kill, ok := event.(hll.KillEvent)
+	if !ok || !isVehicleWeapon(kill.Weapon.ID) {
+		return
+	}
+	if _, ok := ct.weapons[kill.Killer.ID]; !ok {
+		ct.weapons[kill.Killer.ID] = make(map[hll.WeaponIdentifier]time.Time)
+	}
+	ct.weapons[kill.Killer.ID][kill.Weapon.ID] = now
+}
+
+func (ct *crewTracker) process(players []hll.DetailedPlayerInfo, now time.Time) []hll.Event {
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
+
+	for id, weapons := range ct.weapons {
+		maps.DeleteFunc(weapons, func(_ hll.WeaponIdentifier, killed time.Time) bool {
+			return now.Sub(killed) > crewWeaponWindow
+		})
+		if len(weapons) == 0 {
+			delete(ct.weapons, id)
+		}
+	}
+
+	// infantry next to a vehicle would chain the crew together with everyone around it
+	crewmen := slices.DeleteFunc(slices.Clone(players), func(player hll.DetailedPlayerInfo) bool {
+		return player.Role != hll.ROLE_TANKCOMMANDER && player.Role != hll.ROLE_CREWMAN && len(ct.weapons[player.ID]) == 0
+	})
+	groups := []hll.VehicleCrew{}
+	for _, members := range colocatedGroups(crewmen) {
+		if crew, ok := ct.infer(members); ok {
+			groups = append(groups, crew)
+		}
+	}
+
+	// a crew continues with the group sharing the most members
+	events := []hll.Event{}
+	claimed := make(map[int]bool)
+	crews := []*hll.VehicleCrew{}
+	for _, crew := range ct.crews {
+		best, bestShared := -1, 0
+		for index, group := range groups {
+			if claimed[index] || group.Team != crew.Team {
+				continue
+			}
+			shared := 0
+			for _, member := range group.Members {
+				if crew.HasMember(member.ID) {
+					shared++
+				}
+			}
+			if shared > bestShared {
+				best, bestShared = index, shared
+			}
+		}
+		if best < 0 {
+			events = append(events, hll.CrewDissolvedEvent{
+				GenericEvent: hll.GenericEvent{
+					EventType: hll.EVENT_CREW_DISSOLVED,
+					EventTime: now,
+				},
+				Crew: *crew,
+			})
+			continue
+		}
+
+		claimed[best] = true
+		group := groups[best]
+		crew.Members = group.Members
+		crew.Candidates = group.Candidates
+		crew.Weapons = group.Weapons
+		crew.Position = group.Position
+		crew.MaxDistance = max(crew.MaxDistance, crew.Origin.PlanarDistanceTo(group.Position))
+		crew.LastSeen = now
+		crews = append(crews, crew)
+	}
+
+	for index, group := range groups {
+		if claimed[index] {
+			continue
+		}
+		crew := group
+		crew.ID = ct.nextID
+		crew.Origin = group.Position
+		crew.Formed = now
+		crew.LastSeen = now
+		ct.nextID++
+		crews = append(crews, &crew)
+		events = append(events, hll.CrewFormedEvent{
+			GenericEvent: hll.GenericEvent{
+				EventType: hll.EVENT_CREW_FORMED,
+				EventTime: now,
+			},
+			Crew: crew,
+		})
+	}
+	ct.crews = crews
+	return events
+}
+
+// the crew of the members if their roles or recent kills point to a vehicle
+func (ct *crewTracker) infer(members []hll.DetailedPlayerInfo) (hll.VehicleCrew, bool) {
+	if len(members) < crewMinSize {
+		return hll.VehicleCrew{}, false
+	}
+
+	roles := []hll.RoleIdentifier{}
+	used := []hll.WeaponIdentifier{}
+	center := hll.Position{}
+	for _, member := range members {
+		roles = append(roles, member.Role)
+		for weapon := range ct.weapons[member.ID] {
+			if !slices.Contains(used, weapon) {
+				used = append(used, weapon)
+			}
+		}
+		center.X += member.Position.X / float64(len(members))
+		center.Y += member.Position.Y / float64(len(members))
+		center.Z += member.Position.Z / float64(len(members))
+	}
+	slices.Sort(used)
+
+	faction := members[0].Faction
+	candidates := hll.MatchVehicles(faction, roles, used)
+	if len(candidates) == 0 {
+		return hll.VehicleCrew{}, false
+	}
+
+	weapons := []hll.WeaponIdentifier{}
+	for _, weapon := range used {
+		if slices.ContainsFunc(candidates, func(vehicle hll.Vehicle) bool { return vehicle.HasWeapon(weapon) }) {
+			weapons = append(weapons, weapon)
+		}
+	}
+	return hll.VehicleCrew{
+		Team:       members[0].Team,
+		Faction:    faction,
+		Members:    members,
+		Candidates: candidates,
+		Weapons:    weapons,
+		Position:   center,
+	}, true
+}
+
+// the spawned players of a team connected by chains of players within the crew radius, ordered by ID
+func colocatedGroups(players []hll.DetailedPlayerInfo) [][]hll.DetailedPlayerInfo {
+	index := hll.NewSpatialIndex(players, hll.DefaultSpatialCellSize)
+	byID := func(a, b hll.DetailedPlayerInfo) int { return strings.Compare(a.ID, b.ID) }
+
+	sorted := slices.Clone(players)
+	slices.SortFunc(sorted, byID)
+
+	visited := make(map[string]bool)
+	groups := [][]hll.DetailedPlayerInfo{}
+	for _, player := range sorted {
+		if visited[player.ID] || player.Team == hll.TEAM_NONE || !player.IsSpawned() {
+			continue
+		}
+		visited[player.ID] = true
+		group := []hll.DetailedPlayerInfo{player}
+		for i := 0; i < len(group); i++ {
+			for _, other := range index.NearPlayer(group[i].ID, crewRadius) {
+				if visited[other.ID] || other.Team != player.Team {
+					continue
+				}
+				visited[other.ID] = true
+				group = append(group, other)
+			}
+		}
+		slices.SortFunc(group, byID)
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func isVehicleWeapon(weapon hll.WeaponIdentifier) bool {
+	return slices.ContainsFunc(hll.AllVehicles(), func(vehicle hll.Vehicle) bool { return vehicle.HasWeapon(weapon) })
+}
+
+func (r *Rcon) VehicleCrews() ([]hll.VehicleCrew, error) {
+	if !r.Events.enabled {
+		return nil, errEventsDisabled
+	}
+	return r.Events.crews.current(), nil
+}
@@ -0,0 +1,146 @@
+package rcon
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestCrewTracker(t *testing.T) {
+	now := time.Unix(1639148969, 0)
+	player := func(id string, role hll.RoleIdentifier, x float64) hll.DetailedPlayerInfo {
+		return hll.DetailedPlayerInfo{
+			PlayerInfo: hll.PlayerInfo{Name: id, ID: id},
+			Team:       hll.TEAM_ALLIES,
+			Faction:    hll.FACTION_US,
+			Role:       role,
+			Position:   hll.Position{X: x, Y: 1000, Z: 100},
+		}
+	}
+
+	t.Run("Co-located crewmen should form a crew until they split up", func(t *testing.T) {
+		ct := newCrewTracker()
+		events := ct.process([]hll.DetailedPlayerInfo{
+			player("1", hll.ROLE_TANKCOMMANDER, 0),
+			player("2", hll.ROLE_CREWMAN, 100),
+			player("3", hll.ROLE_RIFLEMAN, 5000),
+			player("4", hll.ROLE_RIFLEMAN, 5100),
+		}, now)
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+		formed, ok := events[0].(hll.CrewFormedEvent)
+		if !ok {
+			t.Fatalf("Expected CrewFormedEvent, but got %T", events[0])
+		}
+		if len(formed.Crew.Members) != 2 || !formed.Crew.HasMember("1") || !formed.Crew.HasMember("2") {
+			t.Errorf("Expected players 1 and 2 to form a crew, but got %v", formed.Crew.Members)
+		}
+		for _, vehicle := range formed.Crew.Candidates {
+			if !vehicle.RequiresRole(hll.ROLE_CREWMAN) {
+				t.Errorf("Expected only armored vehicles, but got %s", vehicle.Name)
+			}
+		}
+
+		// the crew drives off, infantry next to it does not join
+		events = ct.process([]hll.DetailedPlayerInfo{
+			player("1", hll.ROLE_TANKCOMMANDER, 20000),
+			player("2", hll.ROLE_CREWMAN, 20100),
+			player("3", hll.ROLE_RIFLEMAN, 20200),
+		}, now.Add(time.Minute))
+		if len(events) != 0 {
+			t.Fatalf("Expected no events, but got %v", events)
+		}
+		crews := ct.current()
+		if len(crews) != 1 || crews[0].ID != formed.Crew.ID || len(crews[0].Members) != 2 {
+			t.Fatalf("Expected the crew to continue with 2 members, but got %v", crews)
+		}
+		if crews[0].MaxDistance < 20000 || crews[0].Duration() != time.Minute {
+			t.Errorf("Expected the crew to travel 20000 within a minute, but got %d within %s", crews[0].MaxDistance, crews[0].Duration())
+		}
+
+		events = ct.process([]hll.DetailedPlayerInfo{
+			player("1", hll.ROLE_TANKCOMMANDER, 20000),
+			player("2", hll.ROLE_CREWMAN, 30000),
+		}, now.Add(2*time.Minute))
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+		if dissolved, ok := events[0].(hll.CrewDissolvedEvent); !ok || dissolved.Crew.ID != formed.Crew.ID {
+			t.Errorf("Expected the crew to dissolve, but got %v", events[0])
+		}
+	})
+
+	t.Run("Kills with a vehicle weapon should narrow down the vehicle", func(t *testing.T) {
+		ct := newCrewTracker()
+		ct.processLog(hll.KillEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_KILL, EventTime: now},
+			Killer:       player("2", hll.ROLE_CREWMAN, 0).PlayerInfo,
+			Weapon:       hll.WEAPON_75MM_M3_GUN_SHERMAN_M4A3E2.Weapon(),
+		}, now)
+		ct.process([]hll.DetailedPlayerInfo{
+			player("1", hll.ROLE_TANKCOMMANDER, 0),
+			player("2", hll.ROLE_CREWMAN, 100),
+		}, now.Add(time.Second))
+		crews := ct.current()
+		if len(crews) != 1 {
+			t.Fatalf("Expected 1 crew, but got %d", len(crews))
+		}
+		if vehicle, ok := crews[0].Vehicle(); !ok || vehicle.ID != hll.VEHICLE_SHERMAN_M4A3E2 {
+			t.Errorf("Expected %s, but got %s", hll.VEHICLE_SHERMAN_M4A3E2, vehicle.ID)
+		}
+		if len(crews[0].Weapons) != 1 {
+			t.Errorf("Expected 1 weapon as evidence, but got %v", crews[0].Weapons)
+		}
+
+		// infantry with a stale vehicle kill is no crew
+		ct.process([]hll.DetailedPlayerInfo{
+			player("2", hll.ROLE_RIFLEMAN, 0),
+			player("3", hll.ROLE_RIFLEMAN, 100),
+		}, now.Add(crewWeaponWindow+2*time.Second))
+		if crews := ct.current(); len(crews) != 0 {
+			t.Errorf("Expected no crews, but got %v", crews)
+		}
+	})
+	t.Run("A crew idling among infantry should form on its own", func(t *testing.T) {
+		ct := newCrewTracker()
+		// the infantry spawning at the HQ chains up to the tank
+		players := []hll.DetailedPlayerInfo{
+			player("1", hll.ROLE_TANKCOMMANDER, 0),
+			player("2", hll.ROLE_CREWMAN, 100),
+			player("3", hll.ROLE_CREWMAN, 200),
+		}
+		for i := range 10 {
+			players = append(players, player(fmt.Sprintf("1%d", i), hll.ROLE_RIFLEMAN, float64(300+i*300)))
+		}
+		ct.processLog(hll.KillEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_KILL, EventTime: now},
+			Killer:       players[3].PlayerInfo,
+			Weapon:       hll.WEAPON_M1_GARAND.Weapon(),
+		}, now)
+		ct.process(players, now.Add(time.Second))
+		crews := ct.current()
+		if len(crews) != 1 || len(crews[0].Members) != 3 || len(crews[0].Candidates) == 0 {
+			t.Fatalf("Expected a crew of the 3 crewmen, but got %v", crews)
+		}
+	})
+
+	t.Run("Vehicle weapon evidence should expire on the client clock", func(t *testing.T) {
+		ct := newCrewTracker()
+		// the server clock runs an hour behind
+		ct.processLog(hll.KillEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_KILL, EventTime: now.Add(-time.Hour)},
+			Killer:       player("2", hll.ROLE_RIFLEMAN, 0).PlayerInfo,
+			Weapon:       hll.WEAPON_75MM_M3_GUN_SHERMAN_M4A3E2.Weapon(),
+		}, now)
+		ct.process([]hll.DetailedPlayerInfo{
+			player("1", hll.ROLE_CREWMAN, 0),
+			player("2", hll.ROLE_RIFLEMAN, 100),
+		}, now.Add(time.Second))
+		if crews := ct.current(); len(crews) != 1 || len(crews[0].Weapons) != 1 {
+			t.Errorf("Expected a crew with 1 weapon as evidence, but got %v", crews)
+		}
+	})
+}
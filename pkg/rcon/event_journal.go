@@ -0,0 +1,319 @@
+package rcon
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+	"github.com/zMoooooritz/go-let-loose/pkg/logger"
+)
+
+const (
+	journalPrefix     = "events-"
+	journalExtension  = ".jsonl"
+	journalTimeFormat = "20060102T150405.000000000"
+	maxJournalLine    = 16 * 1024 * 1024
+)
+
+type JournalConfig struct {
+	Directory   string
+	MaxFileSize int64 // uncompressed bytes after which a new file is started, the file is never rotated if not positive
+	Compress    bool  // gzip the journal files
+}
+
+// writes every event as one line of JSON, each journal only ever appends to new files
+type EventJournal struct {
+	config     JournalConfig
+	file       *os.File
+	compressor *gzip.Writer
+	size       int64
+	mutex      sync.Mutex
+}
+
+func NewEventJournal(cfg JournalConfig) (*EventJournal, error) {
+	if err := os.MkdirAll(cfg.Directory, 0o755); err != nil {
+		return nil, err
+	}
+	return &EventJournal{config: cfg}, nil
+}
+
+// the journal can be registered as an observer via Events.Register
+func (j *EventJournal) Notify(event hll.Event) {
+	if err := j.Write(event); err != nil {
+		logger.Error("writing event to journal failed", err)
+	}
+}
+
+func (j *EventJournal) Write(event hll.Event) error {
+	data, err := hll.MarshalEvent(event)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.file == nil {
+		if err := j.open(); err != nil {
+			return err
+		}
+	}
+
+	if j.compressor != nil {
+		if _, err := j.compressor.Write(data); err != nil {
+			return err
+		}
+		// flush every event, so a crash loses as little as possible
+		if err := j.compressor.Flush(); err != nil {
+			return err
+		}
+	} else if _, err := j.file.Write(data); err != nil {
+		return err
+	}
+
+	j.size += int64(len(data))
+	if j.config.MaxFileSize > 0 && j.size >= j.config.MaxFileSize {
+		return j.closeFile()
+	}
+	return nil
+}
+
+func (j *EventJournal) Close() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.closeFile()
+}
+
+func (j *EventJournal) open() error {
+	extension := journalExtension
+	if j.config.Compress {
+		extension += ".gz"
+	}
+
+	// the file names sort chronologically, existing files are never touched
+	var file *os.File
+	created := time.Now().UTC()
+	for {
+		name := journalPrefix + created.Format(journalTimeFormat) + extension
+		var err error
+		file, err = os.OpenFile(filepath.Join(j.config.Directory, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+		created = created.Add(time.Nanosecond)
+	}
+
+	j.file = file
+	j.size = 0
+	if j.config.Compress {
+		j.compressor = gzip.NewWriter(file)
+	}
+	return nil
+}
+
+func (j *EventJournal) closeFile() error {
+	if j.file == nil {
+		return nil
+	}
+
+	var err error
+	if j.compressor != nil {
+		err = j.compressor.Close()
+		j.compressor = nil
+	}
+	err = errors.Join(err, j.file.Close())
+	j.file = nil
+	return err
+}
+
+// returns the journal files of the directory in chronological order
+func JournalFiles(directory string) ([]string, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, journalPrefix) {
+			continue
+		}
+		if strings.HasSuffix(name, journalExtension) || strings.HasSuffix(name, journalExtension+".gz") {
+			files = append(files, filepath.Join(directory, name))
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// reads the events of journal files one after another
+type JournalReader struct {
+	files   []string
+	file    *os.File
+	scanner *bufio.Scanner
+}
+
+func OpenJournal(directory string) (*JournalReader, error) {
+	files, err := JournalFiles(directory)
+	if err != nil {
+		return nil, err
+	}
+	return NewJournalReader(files), nil
+}
+
+func NewJournalReader(files []string) *JournalReader {
+	return &JournalReader{files: files}
+}
+
+// returns io.EOF once all files are read, undecodable lines are logged and skipped
+func (jr *JournalReader) Next() (hll.Event, error) {
+	for {
+		if jr.scanner == nil {
+			if len(jr.files) == 0 {
+				return nil, io.EOF
+			}
+			if err := jr.openNext(); err != nil {
+				return nil, err
+			}
+			if jr.scanner == nil {
+				continue
+			}
+		}
+
+		if jr.scanner.Scan() {
+			line := jr.scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+			event, err := hll.UnmarshalEvent(line)
+			if err != nil {
+				// the last line of a journal that is still written to may be incomplete
+				logger.Error("skipping undecodable journal line", err)
+				continue
+			}
+			return event, nil
+		}
+
+		err := jr.scanner.Err()
+		jr.Close()
+		// the last file might still be written to, so a truncated gzip stream is no error
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, err
+		}
+	}
+}
+
+func (jr *JournalReader) Close() error {
+	jr.scanner = nil
+	if jr.file == nil {
+		return nil
+	}
+	err := jr.file.Close()
+	jr.file = nil
+	return err
+}
+
+func (jr *JournalReader) openNext() error {
+	name := jr.files[0]
+	jr.files = jr.files[1:]
+
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader = file
+	if strings.HasSuffix(name, ".gz") {
+		decompressor, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			if errors.Is(err, io.EOF) { // the file was created, but nothing was written yet
+				return nil
+			}
+			return fmt.Errorf("reading journal %s failed: %w", name, err)
+		}
+		reader = decompressor
+	}
+
+	jr.file = file
+	jr.scanner = bufio.NewScanner(reader)
+	jr.scanner.Buffer(make([]byte, 0, 64*1024), maxJournalLine)
+	return nil
+}
+
+type ReplayConfig struct {
+	Speed float64   // factor relative to the recorded pace, events are replayed as fast as possible if not positive
+	From  time.Time // events before this time are skipped, if set
+	To    time.Time // events after this time are skipped, if set
+}
+
+// feeds the recorded events to the registered callbacks and observers, just as if they were happening live
+func (r *Rcon) ReplayJournal(ctx context.Context, directory string, cfg ReplayConfig) error {
+	if !r.Events.enabled {
+		return errEventsDisabled
+	}
+
+	reader, err := OpenJournal(directory)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	var lastTime time.Time
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if !cfg.From.IsZero() && event.Time().Before(cfg.From) {
+			continue
+		}
+		// the journal is written in the order of arrival, so an earlier event may still follow
+		if !cfg.To.IsZero() && event.Time().After(cfg.To) {
+			continue
+		}
+
+		if cfg.Speed > 0 && !lastTime.IsZero() && event.Time().After(lastTime) {
+			delay := time.Duration(float64(event.Time().Sub(lastTime)) / cfg.Speed)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-r.Events.context.Done():
+				return errEventsClosed
+			case <-time.After(delay):
+			}
+		}
+		// late events must not set the replay back in time
+		if event.Time().After(lastTime) {
+			lastTime = event.Time()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.Events.context.Done():
+			return errEventsClosed
+		case r.Events.replay <- event:
+		}
+	}
+}
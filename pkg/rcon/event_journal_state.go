@@ -0,0 +1,178 @@
+package rcon
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+var errNoSnapshot = errors.New("the journal contains no server snapshot before the requested time")
+
+// the journal is written in the order of arrival, log events may arrive after later polled events
+const journalArrivalSlack = time.Minute
+
+// the reconstructed state of the server at a point in time
+type ServerState struct {
+	Time         time.Time
+	SnapshotTime time.Time // time of the snapshot the state is based on
+	SessionInfo  hll.SessionInfo
+	Layer        hll.Layer
+	Score        hll.TeamData
+	Players      []hll.DetailedPlayerInfo
+}
+
+func (ss ServerState) ServerView() *hll.ServerView {
+	return hll.PlayersToServerView(ss.Players)
+}
+
+func (ss ServerState) Player(playerID string) (hll.DetailedPlayerInfo, bool) {
+	for _, player := range ss.Players {
+		if player.ID == playerID {
+			return player, true
+		}
+	}
+	return hll.DetailedPlayerInfo{}, false
+}
+
+// rebuilds the state of the server at the given time, starting from the last snapshot
+// before that time and applying all recorded changes up to it
+func JournalStateAt(directory string, at time.Time) (ServerState, error) {
+	reader, err := OpenJournal(directory)
+	if err != nil {
+		return ServerState{}, err
+	}
+	defer reader.Close()
+	return stateAt(reader, at)
+}
+
+type eventReader interface {
+	Next() (hll.Event, error)
+}
+
+func stateAt(reader eventReader, at time.Time) (ServerState, error) {
+	builder := newStateBuilder()
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ServerState{}, err
+		}
+		if event.Time().After(at.Add(journalArrivalSlack)) {
+			break
+		}
+		if event.Time().After(at) {
+			continue
+		}
+		builder.apply(event)
+	}
+
+	if builder.snapshotTime.IsZero() {
+		return ServerState{}, errNoSnapshot
+	}
+	return builder.state(at), nil
+}
+
+type stateBuilder struct {
+	snapshotTime time.Time
+	sessionInfo  hll.SessionInfo
+	score        hll.TeamData
+	order        []string
+	players      map[string]hll.DetailedPlayerInfo
+}
+
+func newStateBuilder() *stateBuilder {
+	return &stateBuilder{
+		players: make(map[string]hll.DetailedPlayerInfo),
+	}
+}
+
+func (sb *stateBuilder) apply(event hll.Event) {
+	if snapshot, ok := event.(hll.ServerSnapshotEvent); ok {
+		sb.snapshotTime = snapshot.EventTime
+		sb.sessionInfo = snapshot.SessionInfo
+		sb.score = hll.TeamData{Allies: snapshot.SessionInfo.AlliedScore, Axis: snapshot.SessionInfo.AxisScore}
+		sb.order = []string{}
+		sb.players = make(map[string]hll.DetailedPlayerInfo)
+		for _, player := range snapshot.Players {
+			sb.order = append(sb.order, player.ID)
+			sb.players[player.ID] = player
+		}
+		return
+	}
+
+	// changes are only meaningful on top of a snapshot, late log events from before it are already part of it
+	if sb.snapshotTime.IsZero() || !event.Time().After(sb.snapshotTime) {
+		return
+	}
+
+	switch e := event.(type) {
+	case hll.DisconnectEvent:
+		delete(sb.players, e.Player.ID)
+		sb.order = slices.DeleteFunc(sb.order, func(id string) bool { return id == e.Player.ID })
+	case hll.ObjectiveCaptureEvent:
+		sb.score = e.NewScore
+	case hll.MatchEndEvent:
+		sb.score = e.Score
+	case hll.KillEvent:
+		sb.update(e.Killer.ID, func(p *hll.DetailedPlayerInfo) { p.Kills++ })
+	case hll.DeathEvent:
+		sb.update(e.Victim.ID, func(p *hll.DetailedPlayerInfo) { p.Deaths++ })
+	case hll.TeamKillEvent:
+		sb.update(e.Killer.ID, func(p *hll.DetailedPlayerInfo) { p.TeamKills++ })
+	case hll.TeamDeathEvent:
+		sb.update(e.Victim.ID, func(p *hll.DetailedPlayerInfo) { p.Deaths++ })
+	case hll.PlayerSwitchTeamEvent:
+		sb.update(e.Player.ID, func(p *hll.DetailedPlayerInfo) { p.Team = e.NewTeam })
+	case hll.PlayerSwitchSquadEvent:
+		sb.update(e.Player.ID, func(p *hll.DetailedPlayerInfo) { p.Unit = e.NewSquad })
+	case hll.PlayerChangeRoleEvent:
+		sb.update(e.Player.ID, func(p *hll.DetailedPlayerInfo) { p.Role = e.NewRole })
+	case hll.PlayerChangeLoadoutEvent:
+		sb.update(e.Player.ID, func(p *hll.DetailedPlayerInfo) { p.Loadout = e.NewLoadout })
+	case hll.PlayerScoreUpdateEvent:
+		sb.update(e.Player.ID, func(p *hll.DetailedPlayerInfo) { p.Score = e.NewScore })
+	case hll.PlayerPositionChangedEvent:
+		sb.update(e.Player.ID, func(p *hll.DetailedPlayerInfo) { p.Position = e.NewPos })
+	case hll.PlayerSpawnedEvent:
+		sb.update(e.Player.ID, func(p *hll.DetailedPlayerInfo) { p.Position = e.Position })
+	case hll.PlayerDespawnedEvent:
+		sb.update(e.Player.ID, func(p *hll.DetailedPlayerInfo) { p.Position = hll.Position{} })
+	case hll.PlayerClanTagChangedEvent:
+		sb.update(e.Player.ID, func(p *hll.DetailedPlayerInfo) { p.ClanTag = e.NewClanTag })
+	case hll.PlayerLevelUpEvent:
+		sb.update(e.Player.ID, func(p *hll.DetailedPlayerInfo) { p.Level = e.NewLevel })
+	}
+}
+
+// players that joined after the snapshot are unknown until the next snapshot
+func (sb *stateBuilder) update(playerID string, change func(*hll.DetailedPlayerInfo)) {
+	player, ok := sb.players[playerID]
+	if !ok {
+		return
+	}
+	change(&player)
+	sb.players[playerID] = player
+}
+
+func (sb *stateBuilder) state(at time.Time) ServerState {
+	layer, _ := hll.ParseLayer(sb.sessionInfo.MapID)
+
+	players := []hll.DetailedPlayerInfo{}
+	for _, id := range sb.order {
+		players = append(players, sb.players[id])
+	}
+
+	return ServerState{
+		Time:         at,
+		SnapshotTime: sb.snapshotTime,
+		SessionInfo:  sb.sessionInfo,
+		Layer:        layer,
+		Score:        sb.score,
+		Players:      players,
+	}
+}
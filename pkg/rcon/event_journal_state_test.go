@@ -0,0 +1,142 @@
+package rcon
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestJournalStateAt(t *testing.T) {
+	start := time.Unix(1700000000, 0).UTC()
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+	generic := func(eventType hll.EventType, seconds int) hll.GenericEvent {
+		return hll.GenericEvent{EventType: eventType, EventTime: at(seconds)}
+	}
+
+	alice := hll.DetailedPlayerInfo{
+		PlayerInfo: hll.PlayerInfo{Name: "Alice", ID: "1"},
+		Team:       hll.TEAM_ALLIES,
+		Unit:       hll.Unit{Name: "Able", ID: 0},
+		Role:       hll.ROLE_OFFICER,
+		Loadout:    "Standard Issue",
+		Position:   hll.Position{X: 100, Y: 100, Z: 10},
+	}
+	bob := hll.DetailedPlayerInfo{
+		PlayerInfo: hll.PlayerInfo{Name: "Bob", ID: "2"},
+		Team:       hll.TEAM_AXIS,
+		Unit:       hll.Unit{Name: "Able", ID: 0},
+		Role:       hll.ROLE_RIFLEMAN,
+		Position:   hll.Position{X: 500, Y: 500, Z: 10},
+	}
+
+	dir := t.TempDir()
+	writeJournal(t, JournalConfig{Directory: dir}, []hll.Event{
+		hll.ChatEvent{GenericEvent: generic(hll.EVENT_CHAT, 0), Message: "before any snapshot"},
+		hll.ServerSnapshotEvent{
+			GenericEvent: generic(hll.EVENT_SERVER_SNAPSHOT, 10),
+			SessionInfo:  hll.SessionInfo{MapID: string(hll.LAYER_CARENTAN_WARFARE), AlliedScore: 2, AxisScore: 2},
+			Players:      []hll.DetailedPlayerInfo{alice, bob},
+		},
+		hll.PlayerPositionChangedEvent{GenericEvent: generic(hll.EVENT_POSITION_CHANGED, 11), Player: alice.PlayerInfo, OldPos: alice.Position, NewPos: hll.Position{X: 200, Y: 200, Z: 10}},
+		hll.PlayerChangeLoadoutEvent{GenericEvent: generic(hll.EVENT_LOADOUT_CHANGED, 12), Player: alice.PlayerInfo, OldLoadout: alice.Loadout, NewLoadout: "Officer"},
+		hll.KillEvent{GenericEvent: generic(hll.EVENT_KILL, 13), Killer: alice.PlayerInfo, Victim: bob.PlayerInfo},
+		hll.DeathEvent{GenericEvent: generic(hll.EVENT_DEATH, 13), Killer: alice.PlayerInfo, Victim: bob.PlayerInfo},
+		hll.PlayerDespawnedEvent{GenericEvent: generic(hll.EVENT_PLAYER_DESPAWNED, 14), Player: bob.PlayerInfo, Position: bob.Position},
+		hll.ObjectiveCaptureEvent{GenericEvent: generic(hll.EVENT_OBJECTIVE_CAPPED, 15), OldScore: hll.TeamData{Allies: 2, Axis: 2}, NewScore: hll.TeamData{Allies: 3, Axis: 1}},
+		hll.DisconnectEvent{GenericEvent: generic(hll.EVENT_DISCONNECTED, 30), Player: bob.PlayerInfo},
+	})
+
+	t.Run("before the first snapshot", func(t *testing.T) {
+		if _, err := JournalStateAt(dir, at(5)); !errors.Is(err, errNoSnapshot) {
+			t.Errorf("Expected errNoSnapshot, but got %v", err)
+		}
+	})
+
+	t.Run("changes applied up to the time", func(t *testing.T) {
+		state, err := JournalStateAt(dir, at(20))
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+
+		if state.Layer.ID != hll.LAYER_CARENTAN_WARFARE || state.Score != (hll.TeamData{Allies: 3, Axis: 1}) {
+			t.Errorf("Expected Carentan at 3-1, but got %s at %v", state.Layer.ID, state.Score)
+		}
+		if len(state.Players) != 2 {
+			t.Fatalf("Expected 2 players, but got %d", len(state.Players))
+		}
+
+		player, _ := state.Player(alice.ID)
+		if player.Position != (hll.Position{X: 200, Y: 200, Z: 10}) || player.Loadout != "Officer" || player.Kills != 1 {
+			t.Errorf("Expected the moved officer with one kill, but got %+v", player)
+		}
+		player, _ = state.Player(bob.ID)
+		if player.Position.IsActive() || player.Deaths != 1 {
+			t.Errorf("Expected a dead and despawned player, but got %+v", player)
+		}
+
+		view := state.ServerView()
+		if !view.Allies.HasPlayer(alice.ID) || !view.Axis.HasPlayer(bob.ID) {
+			t.Error("Expected the players in their teams")
+		}
+	})
+
+	t.Run("at the snapshot", func(t *testing.T) {
+		state, err := JournalStateAt(dir, at(10))
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if player, _ := state.Player(alice.ID); player.Position != alice.Position {
+			t.Errorf("Expected the snapshot position, but got %v", player.Position)
+		}
+	})
+
+	t.Run("after a disconnect", func(t *testing.T) {
+		state, err := JournalStateAt(dir, at(40))
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if _, ok := state.Player(bob.ID); ok || len(state.Players) != 1 {
+			t.Errorf("Expected the disconnected player to be gone, but got %v", state.Players)
+		}
+	})
+
+	t.Run("log events arriving late", func(t *testing.T) {
+		dir := t.TempDir()
+		writeJournal(t, JournalConfig{Directory: dir}, []hll.Event{
+			hll.ServerSnapshotEvent{GenericEvent: generic(hll.EVENT_SERVER_SNAPSHOT, 10), Players: []hll.DetailedPlayerInfo{alice, bob}},
+			hll.PlayerPositionChangedEvent{GenericEvent: generic(hll.EVENT_POSITION_CHANGED, 16), Player: alice.PlayerInfo, OldPos: alice.Position, NewPos: hll.Position{X: 200, Y: 200, Z: 10}},
+			// the kill was polled from the logs after the position change
+			hll.KillEvent{GenericEvent: generic(hll.EVENT_KILL, 15), Killer: alice.PlayerInfo, Victim: bob.PlayerInfo},
+		})
+
+		state, err := JournalStateAt(dir, at(15))
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if player, _ := state.Player(alice.ID); player.Kills != 1 || player.Position != alice.Position {
+			t.Errorf("Expected the kill without the later position change, but got %+v", player)
+		}
+	})
+	t.Run("log events from before the snapshot", func(t *testing.T) {
+		dir := t.TempDir()
+		counted := alice
+		counted.Kills = 1
+		writeJournal(t, JournalConfig{Directory: dir}, []hll.Event{
+			hll.ServerSnapshotEvent{GenericEvent: generic(hll.EVENT_SERVER_SNAPSHOT, 10), Players: []hll.DetailedPlayerInfo{counted, bob}},
+			// the kill is already counted by the snapshot but was polled from the logs after it
+			hll.KillEvent{GenericEvent: generic(hll.EVENT_KILL, 9), Killer: alice.PlayerInfo, Victim: bob.PlayerInfo},
+		})
+
+		state, err := JournalStateAt(dir, at(15))
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if player, _ := state.Player(alice.ID); player.Kills != 1 {
+			t.Errorf("Expected the kill to be counted once, but got %d kills", player.Kills)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package rcon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+type countingObserver struct {
+	count int
+}
+
+func (co *countingObserver) Notify(hll.Event) {
+	co.count++
+}
+
+func journalEvents(start time.Time) []hll.Event {
+	return []hll.Event{
+		hll.ConnectEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_CONNECTED, EventTime: start},
+			Player:       hll.PlayerInfo{Name: "Victim", ID: "2"},
+		},
+		hll.KillEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_KILL, EventTime: start.Add(time.Second)},
+			Killer:       hll.PlayerInfo{Name: "Killer", ID: "1"},
+			Victim:       hll.PlayerInfo{Name: "Victim", ID: "2"},
+		},
+		hll.ChatEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_CHAT, EventTime: start.Add(2 * time.Second)},
+			Message:      "gg",
+		},
+	}
+}
+
+func writeJournal(t *testing.T, cfg JournalConfig, events []hll.Event) {
+	journal, err := NewEventJournal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range events {
+		if err := journal.Write(event); err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEventJournal(t *testing.T) {
+	start := time.Unix(1700000000, 0).UTC()
+
+	for _, compress := range []bool{false, true} {
+		name := "plain"
+		if compress {
+			name = "compressed"
+		}
+		t.Run(name, func(t *testing.T) {
+			cfg := JournalConfig{Directory: t.TempDir(), MaxFileSize: 1, Compress: compress}
+			events := journalEvents(start)
+			writeJournal(t, cfg, events)
+
+			files, err := JournalFiles(cfg.Directory)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != len(events) {
+				t.Errorf("Expected one file per event, but got %d files", len(files))
+			}
+
+			reader, err := OpenJournal(cfg.Directory)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer reader.Close()
+
+			for i, expected := range events {
+				event, err := reader.Next()
+				if err != nil {
+					t.Fatalf("Expected event %d, but got %v", i, err)
+				}
+				if event.Type() != expected.Type() || !event.Time().Equal(expected.Time()) {
+					t.Errorf("Expected %s at %v, but got %s at %v", expected.Type(), expected.Time(), event.Type(), event.Time())
+				}
+			}
+			if _, err := reader.Next(); !errors.Is(err, io.EOF) {
+				t.Errorf("Expected io.EOF, but got %v", err)
+			}
+		})
+	}
+}
+
+func TestJournalTruncatedLine(t *testing.T) {
+	cfg := JournalConfig{Directory: t.TempDir()}
+	events := journalEvents(time.Unix(1700000000, 0).UTC())
+	writeJournal(t, cfg, events)
+
+	// the journal is still written to, the last line is incomplete
+	files, err := JournalFiles(cfg.Directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.OpenFile(files[len(files)-1], os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`{"version":1,"type":"KILL","event":{"eventType":"KI`); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	reader, err := OpenJournal(cfg.Directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	for i := range events {
+		if _, err := reader.Next(); err != nil {
+			t.Fatalf("Expected event %d, but got %v", i, err)
+		}
+	}
+	if _, err := reader.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF, but got %v", err)
+	}
+}
+
+func TestReplayJournal(t *testing.T) {
+	cfg := DefaultEventsConfig()
+	cfg.Logs.Disabled = true
+	cfg.ServerInfo.Disabled = true
+	rcn := &Rcon{Events: &rconEvents{enabled: true, eventSystem: *newEventSystem(nil, cfg)}}
+
+	journalCfg := JournalConfig{Directory: t.TempDir()}
+	writeJournal(t, journalCfg, journalEvents(time.Unix(1700000000, 0).UTC()))
+
+	kills := []hll.KillEvent{}
+	rcn.OnKill(func(event hll.KillEvent) {
+		kills = append(kills, event)
+	})
+	counter := &countingObserver{}
+	rcn.Events.Register(counter)
+
+	started := time.Now()
+	err := rcn.ReplayJournal(context.Background(), journalCfg.Directory, ReplayConfig{Speed: 20})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected the replay to take about 100ms, but it took %v", elapsed)
+	}
+	rcn.Events.close()
+
+	if counter.count != 3 {
+		t.Errorf("Expected 3 events, but got %d", counter.count)
+	}
+	if len(kills) != 1 || kills[0].Killer.Name != "Killer" {
+		t.Errorf("Expected the kill to be replayed, but got %v", kills)
+	}
+}
+
+func TestReplayJournalBounds(t *testing.T) {
+	cfg := DefaultEventsConfig()
+	cfg.Logs.Disabled = true
+	cfg.ServerInfo.Disabled = true
+
+	start := time.Unix(1700000000, 0).UTC()
+	journalCfg := JournalConfig{Directory: t.TempDir()}
+	events := journalEvents(start)
+	// the chat arrived before the kill although it happened after the end of the replay
+	writeJournal(t, journalCfg, []hll.Event{events[0], events[2], events[1]})
+
+	t.Run("Events after the end should be skipped", func(t *testing.T) {
+		rcn := &Rcon{Events: &rconEvents{enabled: true, eventSystem: *newEventSystem(nil, cfg)}}
+		counter := &countingObserver{}
+		rcn.Events.Register(counter)
+
+		err := rcn.ReplayJournal(context.Background(), journalCfg.Directory, ReplayConfig{To: start.Add(1500 * time.Millisecond)})
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		rcn.Events.close()
+
+		if counter.count != 2 {
+			t.Errorf("Expected the connect and the kill, but got %d events", counter.count)
+		}
+	})
+
+	t.Run("Late events should not set the replay back in time", func(t *testing.T) {
+		lateCfg := JournalConfig{Directory: t.TempDir()}
+		chat := func(at time.Time) hll.Event {
+			return hll.ChatEvent{GenericEvent: hll.GenericEvent{EventType: hll.EVENT_CHAT, EventTime: at}}
+		}
+		writeJournal(t, lateCfg, []hll.Event{chat(start), chat(start.Add(10 * time.Second)), chat(start), chat(start.Add(10 * time.Second))})
+
+		rcn := &Rcon{Events: &rconEvents{enabled: true, eventSystem: *newEventSystem(nil, cfg)}}
+		defer rcn.Events.close()
+		started := time.Now()
+		if err := rcn.ReplayJournal(context.Background(), lateCfg.Directory, ReplayConfig{Speed: 100}); err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if elapsed := time.Since(started); elapsed > 180*time.Millisecond {
+			t.Errorf("Expected the replay to take about 100ms, but it took %v", elapsed)
+		}
+	})
+
+	t.Run("Replay should stop once the events are closed", func(t *testing.T) {
+		rcn := &Rcon{Events: &rconEvents{enabled: true, eventSystem: *newEventSystem(nil, cfg)}}
+		rcn.Events.close()
+
+		err := rcn.ReplayJournal(context.Background(), journalCfg.Directory, ReplayConfig{})
+		if !errors.Is(err, errEventsClosed) {
+			t.Errorf("Expected %v, but got %v", errEventsClosed, err)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package rcon
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+var (
+	errEventsDisabled = errors.New("events are disabled")
+	errEventsClosed   = errors.New("events are closed")
+)
+
+type matchTracker struct {
+	match hll.Match
+	mutex sync.Mutex
+}
+
+func newMatchTracker() *matchTracker {
+	return &matchTracker{
+		match: hll.Match{Phase: hll.MATCH_PHASE_UNKNOWN},
+	}
+}
+
+func (mt *matchTracker) current() hll.Match {
+	mt.mutex.Lock()
+	defer mt.mutex.Unlock()
+	return mt.match
+}
+
+// the server does not report the phase directly; during the warmup the match timer is not running yet
+func phaseFromSession(sessionInfo hll.SessionInfo) hll.MatchPhase {
+	switch {
+	case sessionInfo.MatchTime <= 0:
+		return hll.MATCH_PHASE_UNKNOWN
+	case sessionInfo.RemainingMatchTime <= 0:
+		return hll.MATCH_PHASE_ENDED
+	case sessionInfo.RemainingMatchTime >= sessionInfo.MatchTime:
+		return hll.MATCH_PHASE_WARMUP
+	default:
+		return hll.MATCH_PHASE_IN_PROGRESS
+	}
+}
+
+func (mt *matchTracker) processSession(sessionInfo hll.SessionInfo, now time.Time) []hll.Event {
+	mt.mutex.Lock()
+	defer mt.mutex.Unlock()
+
+	phase := phaseFromSession(sessionInfo)
+	if phase == hll.MATCH_PHASE_UNKNOWN {
+		return []hll.Event{}
+	}
+	layer, _ := hll.ParseLayer(sessionInfo.MapID)
+
+	// a match started by the log does not know its layer yet, it is taken from the session
+	if mt.match.Phase != hll.MATCH_PHASE_UNKNOWN && mt.match.Layer.ID == "" {
+		mt.match.Layer = layer
+	}
+	newMatch := mt.match.Phase == hll.MATCH_PHASE_UNKNOWN || mt.match.Layer.ID != layer.ID ||
+		(mt.match.Phase == hll.MATCH_PHASE_ENDED && phase == hll.MATCH_PHASE_WARMUP)
+	if newMatch {
+		mt.match = hll.Match{Layer: layer, Phase: hll.MATCH_PHASE_UNKNOWN}
+	} else if mt.match.Phase == hll.MATCH_PHASE_ENDED {
+		// the end of a match is sticky until the next one begins
+		return []hll.Event{}
+	}
+
+	if phase == hll.MATCH_PHASE_IN_PROGRESS && mt.match.StartTime.IsZero() {
+		mt.match.StartTime = now.Add(-(sessionInfo.MatchTime - sessionInfo.RemainingMatchTime))
+	}
+	if phase == hll.MATCH_PHASE_ENDED && mt.match.EndTime.IsZero() {
+		mt.match.EndTime = now
+		mt.match.Score = hll.TeamData{Allies: sessionInfo.AlliedScore, Axis: sessionInfo.AxisScore}
+	}
+	return mt.changePhase(phase, now)
+}
+
+func (mt *matchTracker) processLog(event hll.Event) []hll.Event {
+	mt.mutex.Lock()
+	defer mt.mutex.Unlock()
+
+	switch e := event.(type) {
+	case hll.MatchStartEvent:
+		if mt.match.Phase == hll.MATCH_PHASE_IN_PROGRESS {
+			return []hll.Event{}
+		}
+		if mt.match.Phase == hll.MATCH_PHASE_ENDED {
+			// the next match may be on a different layer
+			mt.match = hll.Match{Phase: hll.MATCH_PHASE_ENDED}
+		}
+		mt.match.StartTime = e.EventTime
+		return mt.changePhase(hll.MATCH_PHASE_IN_PROGRESS, e.EventTime)
+	case hll.MatchEndEvent:
+		if mt.match.Phase == hll.MATCH_PHASE_ENDED {
+			return []hll.Event{}
+		}
+		mt.match.EndTime = e.EventTime
+		mt.match.Score = e.Score
+		return mt.changePhase(hll.MATCH_PHASE_ENDED, e.EventTime)
+	}
+	return []hll.Event{}
+}
+
+func (mt *matchTracker) changePhase(phase hll.MatchPhase, eventTime time.Time) []hll.Event {
+	oldPhase := mt.match.Phase
+	if oldPhase == phase {
+		return []hll.Event{}
+	}
+	mt.match.Phase = phase
+	return []hll.Event{hll.MatchPhaseChangedEvent{
+		GenericEvent: hll.GenericEvent{
+			EventType: hll.EVENT_MATCH_PHASE_CHANGED,
+			EventTime: eventTime,
+		},
+		Match:    mt.match,
+		OldPhase: oldPhase,
+		NewPhase: phase,
+	}}
+}
+
+func (r *Rcon) CurrentMatch() (hll.Match, error) {
+	if !r.Events.enabled {
+		return hll.Match{}, errEventsDisabled
+	}
+	return r.Events.match.current(), nil
+}
@@ -0,0 +1,103 @@
+package rcon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestMatchTracker(t *testing.T) {
+	now := time.Unix(1639148969, 0)
+	session := hll.SessionInfo{
+		MapID:              string(hll.LAYER_CARENTAN_WARFARE),
+		MatchTime:          90 * time.Minute,
+		RemainingMatchTime: 60 * time.Minute,
+	}
+
+	expectPhaseChange := func(t *testing.T, events []hll.Event, oldPhase, newPhase hll.MatchPhase) hll.MatchPhaseChangedEvent {
+		t.Helper()
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+		event, ok := events[0].(hll.MatchPhaseChangedEvent)
+		if !ok {
+			t.Fatalf("Expected MatchPhaseChangedEvent, but got %T", events[0])
+		}
+		if event.OldPhase != oldPhase || event.NewPhase != newPhase {
+			t.Errorf("Expected phase change from %s to %s, but got from %s to %s", oldPhase, newPhase, event.OldPhase, event.NewPhase)
+		}
+		return event
+	}
+
+	mt := newMatchTracker()
+
+	t.Run("Connecting mid-match should emit an in progress phase", func(t *testing.T) {
+		event := expectPhaseChange(t, mt.processSession(session, now), hll.MATCH_PHASE_UNKNOWN, hll.MATCH_PHASE_IN_PROGRESS)
+		if !event.Match.StartTime.Equal(now.Add(-30 * time.Minute)) {
+			t.Errorf("Expected estimated start time %v, but got %v", now.Add(-30*time.Minute), event.Match.StartTime)
+		}
+		if event.Match.Layer.ID != hll.LAYER_CARENTAN_WARFARE {
+			t.Errorf("Expected layer %s, but got %s", hll.LAYER_CARENTAN_WARFARE, event.Match.Layer.ID)
+		}
+		if events := mt.processSession(session, now.Add(time.Second)); len(events) != 0 {
+			t.Errorf("Expected no events, but got %v", events)
+		}
+	})
+
+	t.Run("Match end log should end the match", func(t *testing.T) {
+		end := hll.MatchEndEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_MATCHEND, EventTime: now.Add(time.Minute)},
+			Score:        hll.TeamData{Allies: 5, Axis: 0},
+		}
+		event := expectPhaseChange(t, mt.processLog(end), hll.MATCH_PHASE_IN_PROGRESS, hll.MATCH_PHASE_ENDED)
+		if event.Match.Score != end.Score {
+			t.Errorf("Expected final score %v, but got %v", end.Score, event.Match.Score)
+		}
+		if events := mt.processSession(session, now.Add(2*time.Minute)); len(events) != 0 {
+			t.Errorf("Expected the ended phase to be sticky, but got %v", events)
+		}
+	})
+
+	t.Run("New map should start a new match in warmup", func(t *testing.T) {
+		warmup := session
+		warmup.MapID = string(hll.LAYER_DRIEL_WARFARE)
+		warmup.RemainingMatchTime = warmup.MatchTime
+		expectPhaseChange(t, mt.processSession(warmup, now.Add(3*time.Minute)), hll.MATCH_PHASE_UNKNOWN, hll.MATCH_PHASE_WARMUP)
+
+		start := hll.MatchStartEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_MATCHSTART, EventTime: now.Add(5 * time.Minute)},
+		}
+		event := expectPhaseChange(t, mt.processLog(start), hll.MATCH_PHASE_WARMUP, hll.MATCH_PHASE_IN_PROGRESS)
+		if !event.Match.StartTime.Equal(start.EventTime) || event.Match.Layer.ID != hll.LAYER_DRIEL_WARFARE {
+			t.Errorf("Unexpected match %v", event.Match)
+		}
+
+		current := mt.current()
+		if current.Phase != hll.MATCH_PHASE_IN_PROGRESS {
+			t.Errorf("Expected current phase %s, but got %s", hll.MATCH_PHASE_IN_PROGRESS, current.Phase)
+		}
+	})
+
+	t.Run("Match start log after an end should take the new layer from the next poll", func(t *testing.T) {
+		mt := newMatchTracker()
+		mt.processSession(session, now)
+		mt.processLog(hll.MatchEndEvent{GenericEvent: hll.GenericEvent{EventType: hll.EVENT_MATCHEND, EventTime: now.Add(time.Minute)}})
+
+		start := hll.MatchStartEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_MATCHSTART, EventTime: now.Add(2 * time.Minute)},
+		}
+		expectPhaseChange(t, mt.processLog(start), hll.MATCH_PHASE_ENDED, hll.MATCH_PHASE_IN_PROGRESS)
+
+		next := session
+		next.MapID = string(hll.LAYER_DRIEL_WARFARE)
+		next.RemainingMatchTime = next.MatchTime - time.Minute
+		if events := mt.processSession(next, now.Add(3*time.Minute)); len(events) != 0 {
+			t.Errorf("Expected no events, but got %v", events)
+		}
+		current := mt.current()
+		if current.Layer.ID != hll.LAYER_DRIEL_WARFARE || !current.StartTime.Equal(start.EventTime) {
+			t.Errorf("Expected the match on %s started at %v, but got %s started at %v", hll.LAYER_DRIEL_WARFARE, start.EventTime, current.Layer.ID, current.StartTime)
+		}
+	})
+}
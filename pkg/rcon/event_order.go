@@ -0,0 +1,119 @@
+package rcon
+
+import (
+	"container/heap"
+	"slices"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+// log events are compared by the minimal delay of the log events that arrived within this window
+const clockOffsetWindow = 5 * time.Minute
+
+type bufferedEvent struct {
+	event   hll.Event
+	at      time.Time // the time of the event on the clock of the client
+	arrival uint64
+	release time.Time
+}
+
+type bufferedEvents []bufferedEvent
+
+func (be bufferedEvents) Len() int { return len(be) }
+
+func (be bufferedEvents) Less(i, j int) bool {
+	if !be[i].at.Equal(be[j].at) {
+		return be[i].at.Before(be[j].at)
+	}
+	return be[i].arrival < be[j].arrival
+}
+
+func (be bufferedEvents) Swap(i, j int) { be[i], be[j] = be[j], be[i] }
+
+func (be *bufferedEvents) Push(x any) { *be = append(*be, x.(bufferedEvent)) }
+
+func (be *bufferedEvents) Pop() any {
+	old := *be
+	n := len(old)
+	item := old[n-1]
+	*be = old[:n-1]
+	return item
+}
+
+type clockSample struct {
+	arrival time.Time
+	offset  time.Duration
+}
+
+// holds events back for a fixed window and releases them ordered by their time,
+// events with the same time keep the order of their arrival
+//
+// log events carry the time of the server while all other events carry the time of the client,
+// so the times of log events are shifted by the estimated offset between both clocks
+type reorderBuffer struct {
+	window   time.Duration
+	arrivals uint64
+	events   bufferedEvents
+	samples  []clockSample
+}
+
+func newReorderBuffer(window time.Duration) *reorderBuffer {
+	return &reorderBuffer{
+		window:  window,
+		events:  bufferedEvents{},
+		samples: []clockSample{},
+	}
+}
+
+func (rb *reorderBuffer) push(event hll.Event, now time.Time) {
+	at := event.Time()
+	if event.Source() == hll.EVENT_SOURCE_LOG {
+		at = at.Add(rb.clockOffset(now.Sub(at), now))
+	}
+	rb.arrivals++
+	heap.Push(&rb.events, bufferedEvent{
+		event:   event,
+		at:      at,
+		arrival: rb.arrivals,
+		release: now.Add(rb.window),
+	})
+}
+
+// every log event arrives after it happened, so the smallest delay within the window
+// is the best estimate for the offset of the client clock to the server clock
+func (rb *reorderBuffer) clockOffset(delay time.Duration, now time.Time) time.Duration {
+	rb.samples = append(rb.samples, clockSample{arrival: now, offset: delay})
+	rb.samples = slices.DeleteFunc(rb.samples, func(sample clockSample) bool {
+		return now.Sub(sample.arrival) > clockOffsetWindow
+	})
+	offset := delay
+	for _, sample := range rb.samples {
+		offset = min(offset, sample.offset)
+	}
+	return offset
+}
+
+// returns all events that either waited for the whole window or are older than
+// the window, since no event preceding them can arrive anymore
+func (rb *reorderBuffer) release(now time.Time) []hll.Event {
+	events := []hll.Event{}
+	for rb.events.Len() > 0 {
+		next := rb.events[0]
+		if now.Before(next.release) && now.Sub(next.at) < rb.window {
+			break
+		}
+		heap.Pop(&rb.events)
+		events = append(events, next.event)
+	}
+	return events
+}
+
+// returns all buffered events ordered by their time
+func (rb *reorderBuffer) flush() []hll.Event {
+	events := []hll.Event{}
+	for rb.events.Len() > 0 {
+		events = append(events, heap.Pop(&rb.events).(bufferedEvent).event)
+	}
+	return events
+}
@@ -0,0 +1,146 @@
+package rcon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+	"github.com/zMoooooritz/go-let-loose/pkg/logger"
+)
+
+func TestReorderBuffer(t *testing.T) {
+	start := time.Unix(1000, 0)
+	event := func(eventType hll.EventType, offset time.Duration) hll.Event {
+		return hll.GenericEvent{EventType: eventType, EventTime: start.Add(offset)}
+	}
+
+	t.Run("ordered by time", func(t *testing.T) {
+		rb := newReorderBuffer(2 * time.Second)
+		// the despawn is polled before the death shows up in the logs
+		rb.push(event(hll.EVENT_PLAYER_DESPAWNED, 1200*time.Millisecond), start.Add(1200*time.Millisecond))
+		rb.push(event(hll.EVENT_DEATH, 0), start.Add(1500*time.Millisecond))
+		rb.push(event(hll.EVENT_KILL, 0), start.Add(1500*time.Millisecond))
+
+		if events := rb.release(start.Add(1600 * time.Millisecond)); len(events) != 0 {
+			t.Fatalf("Expected no events within the window, but got %d", len(events))
+		}
+
+		events := rb.release(start.Add(4 * time.Second))
+		expected := []hll.EventType{hll.EVENT_DEATH, hll.EVENT_KILL, hll.EVENT_PLAYER_DESPAWNED}
+		if len(events) != len(expected) {
+			t.Fatalf("Expected %d events, but got %d", len(expected), len(events))
+		}
+		for i, eventType := range expected {
+			if events[i].Type() != eventType {
+				t.Errorf("Expected event %d to be %s, but got %s", i, eventType, events[i].Type())
+			}
+		}
+	})
+
+	t.Run("released once old enough", func(t *testing.T) {
+		rb := newReorderBuffer(2 * time.Second)
+		rb.push(event(hll.EVENT_KILL, 0), start.Add(1500*time.Millisecond))
+		rb.push(event(hll.EVENT_CHAT, 1500*time.Millisecond), start.Add(1500*time.Millisecond))
+
+		events := rb.release(start.Add(2 * time.Second))
+		if len(events) != 1 || events[0].Type() != hll.EVENT_KILL {
+			t.Errorf("Expected only the kill to be released, but got %v", events)
+		}
+	})
+
+	t.Run("log events shifted to the client clock", func(t *testing.T) {
+		// the clock of the server is 30 seconds ahead of the client
+		skew := 30 * time.Second
+		logEvent := func(eventType hll.EventType, offset time.Duration) hll.Event {
+			return hll.WithSource(event(eventType, skew+offset), hll.EVENT_SOURCE_LOG)
+		}
+
+		rb := newReorderBuffer(2 * time.Second)
+		rb.push(logEvent(hll.EVENT_CHAT, 0), start.Add(100*time.Millisecond))
+		rb.push(event(hll.EVENT_PLAYER_DESPAWNED, 1200*time.Millisecond), start.Add(1200*time.Millisecond))
+		rb.push(logEvent(hll.EVENT_DEATH, time.Second), start.Add(1500*time.Millisecond))
+
+		if events := rb.release(start.Add(1600 * time.Millisecond)); len(events) != 0 {
+			t.Fatalf("Expected no events within the window, but got %d", len(events))
+		}
+
+		events := rb.release(start.Add(4 * time.Second))
+		expected := []hll.EventType{hll.EVENT_CHAT, hll.EVENT_DEATH, hll.EVENT_PLAYER_DESPAWNED}
+		if len(events) != len(expected) {
+			t.Fatalf("Expected %d events, but got %d", len(expected), len(events))
+		}
+		for i, eventType := range expected {
+			if events[i].Type() != eventType {
+				t.Errorf("Expected event %d to be %s, but got %s", i, eventType, events[i].Type())
+			}
+		}
+		if !events[1].Time().Equal(start.Add(skew + time.Second)) {
+			t.Errorf("Expected the time of the death to be kept, but got %v", events[1].Time())
+		}
+	})
+
+	t.Run("flushed in order", func(t *testing.T) {
+		rb := newReorderBuffer(2 * time.Second)
+		rb.push(event(hll.EVENT_CHAT, time.Second), start.Add(time.Second))
+		rb.push(event(hll.EVENT_KILL, 0), start.Add(time.Second))
+
+		events := rb.flush()
+		if len(events) != 2 || events[0].Type() != hll.EVENT_KILL || events[1].Type() != hll.EVENT_CHAT {
+			t.Errorf("Expected the kill and the chat, but got %v", events)
+		}
+		if rb.events.Len() != 0 {
+			t.Errorf("Expected an empty buffer, but got %d events", rb.events.Len())
+		}
+	})
+}
+
+func TestEventMetadata(t *testing.T) {
+	kill := hll.KillEvent{
+		GenericEvent: hll.GenericEvent{EventType: hll.EVENT_KILL, EventTime: time.Now()},
+		Killer:       hll.PlayerInfo{Name: "Killer", ID: "1"},
+	}
+
+	event := hll.WithSequence(hll.WithSource(kill, hll.EVENT_SOURCE_LOG), 42)
+	stamped, ok := event.(hll.KillEvent)
+	if !ok {
+		t.Fatalf("Expected KillEvent, but got %T", event)
+	}
+	if stamped.Sequence() != 42 || stamped.Source() != hll.EVENT_SOURCE_LOG {
+		t.Errorf("Expected sequence 42 from the logs, but got %d from %q", stamped.Sequence(), stamped.Source())
+	}
+	if stamped.Killer != kill.Killer {
+		t.Errorf("Expected the killer to be kept, but got %v", stamped.Killer)
+	}
+	if kill.Sequence() != 0 {
+		t.Error("Expected the original event to be unchanged")
+	}
+
+	generic := hll.WithSequence(hll.GenericEvent{EventType: hll.EVENT_GENERIC}, 7)
+	if generic.Sequence() != 7 {
+		t.Errorf("Expected sequence 7, but got %d", generic.Sequence())
+	}
+
+	t.Run("pointer events are left unchanged with a warning", func(t *testing.T) {
+		warnings := &recordingLogger{}
+		logger.SetLogger(warnings)
+		defer logger.SetLogger(nil)
+
+		event := hll.WithSequence(&kill, 42)
+		if event != &kill || kill.Sequence() != 0 {
+			t.Errorf("Expected the event to be unchanged, but got %v", event)
+		}
+		if len(warnings.warnings) != 1 {
+			t.Errorf("Expected 1 warning, but got %v", warnings.warnings)
+		}
+	})
+}
+
+type recordingLogger struct {
+	warnings []string
+}
+
+func (rl *recordingLogger) Debug(msg string) {}
+func (rl *recordingLogger) Info(msg string)  {}
+func (rl *recordingLogger) Warn(msg string)  { rl.warnings = append(rl.warnings, msg) }
+func (rl *recordingLogger) Error(msg string) {}
+func (rl *recordingLogger) Fatal(msg string) {}
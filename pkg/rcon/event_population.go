@@ -0,0 +1,55 @@
+package rcon
+
+import (
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+// the player count has to drop this far below a threshold before it counts as crossed again, so a count
+// oscillating around a threshold does not emit an event on every poll
+const populationMargin = 3
+
+type populationTracker struct {
+	thresholds []int
+	above      map[int]bool
+	lastCount  int
+	known      bool
+}
+
+func newPopulationTracker(thresholds []int) *populationTracker {
+	return &populationTracker{
+		thresholds: thresholds,
+		above:      make(map[int]bool),
+	}
+}
+
+func (pt *populationTracker) process(playerCount int, now time.Time) []hll.Event {
+	events := []hll.Event{}
+	for _, threshold := range pt.thresholds {
+		above := pt.above[threshold]
+		rising := !above && playerCount >= threshold
+		falling := above && playerCount < threshold-populationMargin
+		if !rising && !falling {
+			continue
+		}
+		pt.above[threshold] = rising
+		// the first poll only sets the initial state
+		if !pt.known {
+			continue
+		}
+		events = append(events, hll.PopulationThresholdCrossedEvent{
+			GenericEvent: hll.GenericEvent{
+				EventType: hll.EVENT_POPULATION_CROSSED,
+				EventTime: now,
+			},
+			Threshold: threshold,
+			OldCount:  pt.lastCount,
+			NewCount:  playerCount,
+			Rising:    rising,
+		})
+	}
+	pt.lastCount = playerCount
+	pt.known = true
+	return events
+}
@@ -0,0 +1,52 @@
+package rcon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestPopulationTracker(t *testing.T) {
+	now := time.Unix(1639148969, 0)
+
+	t.Run("Crossing thresholds should emit events", func(t *testing.T) {
+		pt := newPopulationTracker([]int{20, 40, 70, 80})
+		if events := pt.process(38, now); len(events) != 0 {
+			t.Fatalf("Expected no events on the first poll, but got %v", events)
+		}
+
+		events := pt.process(72, now)
+		if len(events) != 2 {
+			t.Fatalf("Expected 2 events, but got %d", len(events))
+		}
+		for i, threshold := range []int{40, 70} {
+			event := events[i].(hll.PopulationThresholdCrossedEvent)
+			if event.Threshold != threshold || !event.Rising || event.OldCount != 38 || event.NewCount != 72 {
+				t.Errorf("Expected rising threshold %d from 38 to 72, but got %+v", threshold, event)
+			}
+		}
+
+		events = pt.process(30, now)
+		if len(events) != 2 || events[0].(hll.PopulationThresholdCrossedEvent).Rising {
+			t.Errorf("Expected 2 falling threshold events, but got %+v", events)
+		}
+	})
+
+	t.Run("A count oscillating around a threshold should emit once", func(t *testing.T) {
+		pt := newPopulationTracker([]int{20})
+		pt.process(18, now)
+		crossings := 0
+		for _, count := range []int{20, 19, 20, 18, 21, 19} {
+			crossings += len(pt.process(count, now))
+		}
+		if crossings != 1 {
+			t.Errorf("Expected 1 event, but got %d", crossings)
+		}
+
+		events := pt.process(20-populationMargin-1, now)
+		if len(events) != 1 || events[0].(hll.PopulationThresholdCrossedEvent).Rising {
+			t.Errorf("Expected a falling threshold event below the margin, but got %+v", events)
+		}
+	})
+}
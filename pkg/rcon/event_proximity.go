@@ -0,0 +1,115 @@
+package rcon
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+// an EnemyProximityEvent is emitted once an enemy comes within the radius of a watched player
+type ProximityRule struct {
+	Name   string               // passed on with the event to tell the rules apart
+	Roles  []hll.RoleIdentifier // the roles of the watched players, every role if empty
+	Radius float64              // in cm
+}
+
+func DefaultProximityRules() []ProximityRule {
+	return []ProximityRule{
+		{Name: "commander", Roles: []hll.RoleIdentifier{hll.ROLE_ARMYCOMMANDER}, Radius: 5000},
+	}
+}
+
+type proximityPair struct {
+	rule   string
+	player string
+	enemy  string
+}
+
+// keeps the spatial index of the latest player list and the enemies near the watched players
+type proximityTracker struct {
+	rules []ProximityRule
+	index *hll.SpatialIndex
+	near  map[proximityPair]bool
+	mutex sync.Mutex
+}
+
+func newProximityTracker(rules []ProximityRule) *proximityTracker {
+	return &proximityTracker{
+		rules: rules,
+		index: hll.NewSpatialIndex([]hll.DetailedPlayerInfo{}, hll.DefaultSpatialCellSize),
+		near:  make(map[proximityPair]bool),
+	}
+}
+
+func (pt *proximityTracker) current() *hll.SpatialIndex {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	return pt.index
+}
+
+func (pt *proximityTracker) process(players []hll.DetailedPlayerInfo, now time.Time) []hll.Event {
+	index := hll.NewSpatialIndex(players, hll.DefaultSpatialCellSize)
+
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	pt.index = index
+	events := []hll.Event{}
+	near := make(map[proximityPair]bool)
+	for _, rule := range pt.rules {
+		for _, player := range players {
+			if len(rule.Roles) > 0 && !slices.Contains(rule.Roles, player.Role) {
+				continue
+			}
+			for _, enemy := range index.EnemiesNear(player.ID, rule.Radius) {
+				pair := proximityPair{rule: rule.Name, player: player.ID, enemy: enemy.ID}
+				near[pair] = true
+				if pt.near[pair] {
+					continue
+				}
+				events = append(events, hll.EnemyProximityEvent{
+					GenericEvent: hll.GenericEvent{
+						EventType: hll.EVENT_ENEMY_PROXIMITY,
+						EventTime: now,
+					},
+					Rule:     rule.Name,
+					Player:   player.PlayerInfo,
+					Role:     player.Role,
+					Enemy:    enemy.PlayerInfo,
+					Distance: player.Position.PlanarDistanceTo(enemy.Position),
+				})
+			}
+		}
+	}
+	pt.near = near
+	return events
+}
+
+// the spatial index over the spawned players of the latest poll
+func (r *Rcon) SpatialIndex() (*hll.SpatialIndex, error) {
+	if !r.Events.enabled {
+		return nil, errEventsDisabled
+	}
+	return r.Events.proximity.current(), nil
+}
+
+// the other spawned players within the radius in cm of the player as of the latest poll, nearest first
+func (r *Rcon) PlayersNear(playerID string, radius float64) ([]hll.DetailedPlayerInfo, error) {
+	index, err := r.SpatialIndex()
+	if err != nil {
+		return nil, err
+	}
+	return index.NearPlayer(playerID, radius), nil
+}
+
+// up to count spawned enemies of the player as of the latest poll, nearest first
+func (r *Rcon) NearestEnemies(playerID string, count int) ([]hll.DetailedPlayerInfo, error) {
+	index, err := r.SpatialIndex()
+	if err != nil {
+		return nil, err
+	}
+	return index.NearestEnemies(playerID, count), nil
+}
@@ -0,0 +1,57 @@
+package rcon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestProximityTracker(t *testing.T) {
+	now := time.Unix(1639148969, 0)
+	player := func(id string, team hll.TeamIdentifier, role hll.RoleIdentifier, x float64) hll.DetailedPlayerInfo {
+		return hll.DetailedPlayerInfo{PlayerInfo: hll.PlayerInfo{Name: id, ID: id}, Team: team, Role: role, Position: hll.Position{X: x, Y: 1000, Z: 100}}
+	}
+
+	pt := newProximityTracker(DefaultProximityRules())
+	commander := player("1", hll.TEAM_ALLIES, hll.ROLE_ARMYCOMMANDER, 0)
+	rifleman := player("2", hll.TEAM_ALLIES, hll.ROLE_RIFLEMAN, 1000)
+
+	t.Run("An enemy approaching the commander should emit a single event", func(t *testing.T) {
+		events := pt.process([]hll.DetailedPlayerInfo{commander, rifleman, player("3", hll.TEAM_AXIS, hll.ROLE_RIFLEMAN, 8000)}, now)
+		if len(events) != 0 {
+			t.Fatalf("Expected no events, but got %v", events)
+		}
+
+		events = pt.process([]hll.DetailedPlayerInfo{commander, rifleman, player("3", hll.TEAM_AXIS, hll.ROLE_RIFLEMAN, 4000)}, now.Add(time.Second))
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+		proximity, ok := events[0].(hll.EnemyProximityEvent)
+		if !ok {
+			t.Fatalf("Expected EnemyProximityEvent, but got %T", events[0])
+		}
+		if proximity.Rule != "commander" || proximity.Player.ID != "1" || proximity.Enemy.ID != "3" || proximity.Distance != 4000 {
+			t.Errorf("Expected enemy 3 at 4000 of commander 1, but got %s at %d of %s for %s", proximity.Enemy.ID, proximity.Distance, proximity.Player.ID, proximity.Rule)
+		}
+
+		events = pt.process([]hll.DetailedPlayerInfo{commander, rifleman, player("3", hll.TEAM_AXIS, hll.ROLE_RIFLEMAN, 3000)}, now.Add(2*time.Second))
+		if len(events) != 0 {
+			t.Errorf("Expected no events while the enemy stays close, but got %v", events)
+		}
+	})
+
+	t.Run("An enemy returning after leaving should emit again", func(t *testing.T) {
+		pt.process([]hll.DetailedPlayerInfo{commander, rifleman, player("3", hll.TEAM_AXIS, hll.ROLE_RIFLEMAN, 9000)}, now.Add(3*time.Second))
+		events := pt.process([]hll.DetailedPlayerInfo{commander, rifleman, player("3", hll.TEAM_AXIS, hll.ROLE_RIFLEMAN, 2000)}, now.Add(4*time.Second))
+		if len(events) != 1 {
+			t.Errorf("Expected 1 event, but got %d", len(events))
+		}
+	})
+
+	t.Run("The latest players should be indexed", func(t *testing.T) {
+		if nearest := pt.current().NearestEnemies("2", 1); len(nearest) != 1 || nearest[0].ID != "3" {
+			t.Errorf("Expected player 3 to be the nearest enemy, but got %v", nearest)
+		}
+	})
+}
@@ -17,29 +17,87 @@ var players *ttlcache.Cache[string, hll.DetailedPlayerInfo] = ttlcache.New(
 	ttlcache.WithDisableTouchOnHit[string, hll.DetailedPlayerInfo](),
 )
 
-func eventHandlerRoutine(events <-chan hll.Event, eventNotifier *eventNotifier, ctx context.Context, wg *sync.WaitGroup) {
+var recentDeaths *ttlcache.Cache[string, hll.PlayerInfo] = ttlcache.New(
+	ttlcache.WithTTL[string, hll.PlayerInfo](10*time.Second),
+	ttlcache.WithDisableTouchOnHit[string, hll.PlayerInfo](),
+)
+
+var lastPositions *ttlcache.Cache[string, hll.Position] = ttlcache.New(
+	ttlcache.WithTTL[string, hll.Position](2*time.Minute),
+	ttlcache.WithDisableTouchOnHit[string, hll.Position](),
+)
+
+func eventHandlerRoutine(events <-chan hll.Event, replay <-chan hll.Event, eventNotifier *eventNotifier, cfg EventsConfig, ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 
+	sequence := uint64(0)
+	deliver := func(event hll.Event) {
+		if cfg.isDisabled(event.Type()) {
+			return
+		}
+		sequence++
+		eventNotifier.notify(hll.WithSequence(event, sequence))
+	}
+
+	// without a reorder window the events are delivered in the order of their arrival
+	var buffer *reorderBuffer
+	var tick <-chan time.Time
+	if cfg.ReorderWindow > 0 {
+		buffer = newReorderBuffer(cfg.ReorderWindow)
+		ticker := time.NewTicker(reorderInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	// the events still held back are delivered once the system shuts down
+	flush := func() {
+		if buffer == nil {
+			return
+		}
+		for _, event := range buffer.flush() {
+			deliver(event)
+		}
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
+			flush()
 			return
 		case event, ok := <-events:
 			if !ok {
+				flush()
 				return
 			}
+			if buffer == nil {
+				deliver(event)
+				continue
+			}
+			buffer.push(event, time.Now())
+		case now := <-tick:
+			for _, event := range buffer.release(now) {
+				deliver(event)
+			}
+		case event := <-replay:
+			// replayed events were already delivered once, so they are passed on unchanged
 			eventNotifier.notify(event)
 		}
 	}
 }
 
-func logsFetcherRoutine(rcn *Rcon, events chan<- hll.Event, ctx context.Context, wg *sync.WaitGroup) {
+func logsFetcherRoutine(rcn *Rcon, events chan<- hll.Event, cfg EventsConfig, trackers *eventTrackers, ctx context.Context, wg *sync.WaitGroup) {
 	initialRun := true
 	lastSeenTime := int64(0)
 	processedLogs := make(map[string]bool)
 
 	defer wg.Done()
 
+	emit := func(event hll.Event) {
+		if !cfg.isDisabled(event.Type()) {
+			events <- hll.WithSource(event, hll.EVENT_SOURCE_LOG)
+		}
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -70,8 +128,24 @@ func logsFetcherRoutine(rcn *Rcon, events chan<- hll.Event, ctx context.Context,
 				}
 
 				if !initialRun { // ignore past events on startup
-					for _, event := range logToEvents(entry.Message) {
-						events <- event
+					for _, event := range enrichKillEvents(logToEvents(entry.Message)) {
+						recordDeath(event)
+						emit(event)
+						for _, streakEvent := range trackers.streaks.process(event) {
+							emit(streakEvent)
+						}
+						for _, matchEvent := range trackers.match.processLog(event) {
+							rcn.layout.processMatch(matchEvent)
+							trackers.suspicion.process(matchEvent)
+							emit(matchEvent)
+						}
+						for _, sessionEvent := range trackers.sessions.processLog(event) {
+							emit(sessionEvent)
+						}
+						trackers.crews.processLog(event, time.Now())
+						for _, suspicionEvent := range trackers.suspicion.process(event) {
+							emit(suspicionEvent)
+						}
 					}
 				}
 
@@ -85,15 +159,32 @@ func logsFetcherRoutine(rcn *Rcon, events chan<- hll.Event, ctx context.Context,
 
 			initialRun = false
 
-			time.Sleep(400 * time.Millisecond)
+			time.Sleep(cfg.Logs.Interval)
 		}
 	}
 }
 
-func serverInfoFetcherRoutine(rcn *Rcon, events chan<- hll.Event, ctx context.Context, wg *sync.WaitGroup) {
+func serverInfoFetcherRoutine(rcn *Rcon, events chan<- hll.Event, cfg EventsConfig, trackers *eventTrackers, ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 
+	emit := func(event hll.Event) {
+		if !cfg.isDisabled(event.Type()) {
+			events <- hll.WithSource(event, hll.EVENT_SOURCE_POLL)
+		}
+	}
+
+	// the diffs are skipped if none of their events is delivered
+	diffSession := cfg.anyEnabled(hll.EVENT_SERVER_FULL, hll.EVENT_SERVER_NOT_FULL, hll.EVENT_QUEUE_CHANGED, hll.EVENT_VIP_QUEUE_CHANGED)
+	diffPlayers := cfg.anyEnabled(playerDiffEvents...)
+	diffView := cfg.anyEnabled(serverViewDiffEvents...)
+
+	var oldSessionInfo hll.SessionInfo
 	var oldGameState hll.GameState
+	var currentLayer hll.Layer
+	var lastPlayers []hll.DetailedPlayerInfo
+	var lastView *hll.ServerView
+	var lastSnapshot time.Time
+	movement := newMovementFilter(cfg.MinMovement)
 
 	for {
 		select {
@@ -101,37 +192,143 @@ func serverInfoFetcherRoutine(rcn *Rcon, events chan<- hll.Event, ctx context.Co
 			return
 		default:
 
-			gameState, err := rcn.GetGameState()
-			if err == nil {
+			sessionInfo, err := rcn.GetSessionInfo()
+			sessionFetched := err == nil
+			if sessionFetched {
+				for _, event := range trackers.match.processSession(sessionInfo, time.Now()) {
+					rcn.layout.processMatch(event)
+					trackers.suspicion.process(event)
+					emit(event)
+				}
+
+				if diffSession {
+					for _, event := range sessionInfoDiffToEvents(oldSessionInfo, sessionInfo) {
+						emit(event)
+					}
+				}
+				for _, event := range trackers.population.process(sessionInfo.PlayerCount, time.Now()) {
+					emit(event)
+				}
+				oldSessionInfo = sessionInfo
+
+				gameState := toGameState(sessionInfo)
 				if oldGameState != (hll.GameState{}) {
 					stateEvents := gameStateDiffToEvents(oldGameState, gameState)
 					for _, event := range stateEvents {
-						events <- event
+						emit(event)
+						if captureEvent, ok := event.(hll.ObjectiveCaptureEvent); ok {
+							layout := rcn.layout.get(gameState.CurrentMap.ID)
+							for _, sectorEvent := range objectiveCaptureToSectorEvents(captureEvent, gameState.CurrentMap, layout, lastPlayers) {
+								emit(sectorEvent)
+							}
+						}
 					}
 				}
 
 				oldGameState = gameState
+				currentLayer = gameState.CurrentMap
 			}
 
 			players, err := rcn.GetPlayersInfo()
 			if err == nil {
 				for _, player := range players {
 					oldPlayerData, err := getPlayerInfo(player.ID)
-					if err == nil {
-						playerEvents := playerInfoDiffToEvents(oldPlayerData, player)
-						for _, event := range playerEvents {
-							events <- event
+					if err == nil && diffPlayers {
+						playerEvents := playerInfoDiffToEvents(oldPlayerData, player, currentLayer)
+						for _, event := range movement.filter(playerEvents) {
+							emit(event)
 						}
 					}
 					setPlayerInfo(player)
 				}
+				lastPlayers = players
+
+				for _, event := range trackers.sessions.processPlayers(players, time.Now()) {
+					emit(event)
+				}
+				if currentLayer.ID != "" {
+					layout := rcn.layout.get(currentLayer.ID)
+					for _, event := range trackers.strongpoints.process(currentLayer, layout, players, time.Now()) {
+						emit(event)
+					}
+				}
+				for _, event := range trackers.proximity.process(players, time.Now()) {
+					emit(event)
+				}
+				for _, event := range trackers.crews.process(players, time.Now()) {
+					emit(event)
+				}
+
+				if diffView {
+					view := hll.PlayersToServerView(players)
+					if lastView != nil {
+						for _, event := range serverViewDiffToEvents(hll.DiffServerViews(lastView, view)) {
+							emit(event)
+						}
+					}
+					lastView = view
+				}
+
+				// the session info of an earlier poll might belong to the previous layer
+				if cfg.SnapshotInterval > 0 && !cfg.isDisabled(hll.EVENT_SERVER_SNAPSHOT) && sessionFetched && time.Since(lastSnapshot) >= cfg.SnapshotInterval {
+					lastSnapshot = time.Now()
+					emit(hll.ServerSnapshotEvent{
+						GenericEvent: hll.GenericEvent{
+							EventType: hll.EVENT_SERVER_SNAPSHOT,
+							EventTime: lastSnapshot,
+						},
+						SessionInfo: sessionInfo,
+						Players:     players,
+					})
+				}
 			}
 
-			time.Sleep(time.Second)
+			time.Sleep(cfg.ServerInfo.Interval)
 		}
 	}
 }
 
+// suppresses position changes below the minimal movement, the distance is measured
+// from the last reported position so slow movement is still reported eventually
+type movementFilter struct {
+	minMovement int
+	reported    map[string]hll.Position
+}
+
+func newMovementFilter(minMovement int) *movementFilter {
+	return &movementFilter{
+		minMovement: minMovement,
+		reported:    make(map[string]hll.Position),
+	}
+}
+
+func (mf *movementFilter) filter(events []hll.Event) []hll.Event {
+	if mf.minMovement <= 0 {
+		return events
+	}
+
+	filtered := []hll.Event{}
+	for _, event := range events {
+		switch e := event.(type) {
+		case hll.PlayerPositionChangedEvent:
+			if reported, ok := mf.reported[e.Player.ID]; ok {
+				if e.NewPos.SpacialDistanceTo(reported) < mf.minMovement {
+					continue
+				}
+				e.OldPos = reported
+			}
+			mf.reported[e.Player.ID] = e.NewPos
+			event = e
+		case hll.PlayerSpawnedEvent:
+			mf.reported[e.Player.ID] = e.Position
+		case hll.PlayerDespawnedEvent:
+			delete(mf.reported, e.Player.ID)
+		}
+		filtered = append(filtered, event)
+	}
+	return filtered
+}
+
 func gameStateDiffToEvents(oldData hll.GameState, newData hll.GameState) []hll.Event {
 	events := []hll.Event{}
 
@@ -140,6 +337,10 @@ func gameStateDiffToEvents(oldData hll.GameState, newData hll.GameState) []hll.E
 		return events
 	}
 
+	if oldData.CurrentMap.ID != newData.CurrentMap.ID { // map changed, the score got reset
+		return events
+	}
+
 	if oldData.GameScore != newData.GameScore {
 		if newData.GameScore.Axis == newData.GameScore.Allies { // game just started
 			return events
@@ -156,7 +357,196 @@ func gameStateDiffToEvents(oldData hll.GameState, newData hll.GameState) []hll.E
 	return events
 }
 
-func playerInfoDiffToEvents(oldData hll.DetailedPlayerInfo, newData hll.DetailedPlayerInfo) []hll.Event {
+func sessionInfoDiffToEvents(oldData hll.SessionInfo, newData hll.SessionInfo) []hll.Event {
+	events := []hll.Event{}
+
+	if oldData == (hll.SessionInfo{}) {
+		return events
+	}
+
+	genericEvent := func(eventType hll.EventType) hll.GenericEvent {
+		return hll.GenericEvent{
+			EventType: eventType,
+			EventTime: time.Now(),
+		}
+	}
+
+	wasFull := oldData.MaxPlayerCount > 0 && oldData.PlayerCount >= oldData.MaxPlayerCount
+	isFull := newData.MaxPlayerCount > 0 && newData.PlayerCount >= newData.MaxPlayerCount
+	if !wasFull && isFull {
+		events = append(events, hll.ServerFullEvent{
+			GenericEvent:   genericEvent(hll.EVENT_SERVER_FULL),
+			PlayerCount:    newData.PlayerCount,
+			MaxPlayerCount: newData.MaxPlayerCount,
+			QueueCount:     newData.QueueCount,
+		})
+	} else if wasFull && !isFull {
+		events = append(events, hll.ServerNotFullEvent{
+			GenericEvent:   genericEvent(hll.EVENT_SERVER_NOT_FULL),
+			PlayerCount:    newData.PlayerCount,
+			MaxPlayerCount: newData.MaxPlayerCount,
+		})
+	}
+
+	if oldData.QueueCount != newData.QueueCount {
+		events = append(events, hll.QueueChangedEvent{
+			GenericEvent: genericEvent(hll.EVENT_QUEUE_CHANGED),
+			OldCount:     oldData.QueueCount,
+			NewCount:     newData.QueueCount,
+			MaxCount:     newData.MaxQueueCount,
+		})
+	}
+
+	if oldData.VIPQueueCount != newData.VIPQueueCount {
+		events = append(events, hll.VIPQueueChangedEvent{
+			GenericEvent: genericEvent(hll.EVENT_VIP_QUEUE_CHANGED),
+			OldCount:     oldData.VIPQueueCount,
+			NewCount:     newData.VIPQueueCount,
+			MaxCount:     newData.MaxVIPQueueCount,
+		})
+	}
+
+	return events
+}
+
+func objectiveCaptureToSectorEvents(event hll.ObjectiveCaptureEvent, layer hll.Layer, layout []string, players []hll.DetailedPlayerInfo) []hll.Event {
+	team, index, err := layer.CapturedSector(event.OldScore, event.NewScore)
+	if err != nil {
+		return []hll.Event{}
+	}
+	sectors, _ := layer.OrderedSectors()
+	sector := sectors[index]
+
+	sectorEvent := hll.SectorCapturedEvent{
+		GenericEvent: hll.GenericEvent{
+			EventType: hll.EVENT_SECTOR_CAPTURED,
+			EventTime: event.EventTime,
+		},
+		Team:        team,
+		SectorIndex: index,
+		Sector:      sector,
+		OldScore:    event.OldScore,
+		NewScore:    event.NewScore,
+	}
+	if strongpoint, ok := activeStrongpoint(sector, team, layout, players); ok {
+		sectorEvent.Strongpoint = &strongpoint
+	}
+	return []hll.Event{sectorEvent}
+}
+
+// prefer the known sector layout; otherwise guess by the capturing players inside the strongpoints
+func activeStrongpoint(sector hll.Sector, team hll.TeamIdentifier, layout []string, players []hll.DetailedPlayerInfo) (hll.Strongpoint, bool) {
+	for _, objective := range layout {
+		if strongpoint, ok := sector.StrongpointByName(objective); ok {
+			return strongpoint, true
+		}
+	}
+	if len(sector.CaptureZones) == 1 {
+		return sector.CaptureZones[0].Strongpoint, true
+	}
+
+	var best hll.Strongpoint
+	bestCount := 0
+	for _, zone := range sector.CaptureZones {
+		count := 0
+		for _, player := range players {
+			if player.Team == team && player.IsSpawned() && zone.Strongpoint.IsInside(player.Position) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = zone.Strongpoint, count
+		}
+	}
+	return best, bestCount > 0
+}
+
+var serverViewDiffEvents = []hll.EventType{
+	hll.EVENT_COMMANDER_TOOK_ROLE,
+	hll.EVENT_COMMANDER_LEFT_ROLE,
+	hll.EVENT_SQUAD_CREATED,
+	hll.EVENT_SQUAD_DISBANDED,
+	hll.EVENT_SQUAD_LEADER_CHANGED,
+	hll.EVENT_SQUAD_LEADERLESS,
+}
+
+func serverViewDiffToEvents(diff hll.ServerViewDiff) []hll.Event {
+	events := []hll.Event{}
+
+	genericEvent := func(eventType hll.EventType) hll.GenericEvent {
+		return hll.GenericEvent{
+			EventType: eventType,
+			EventTime: time.Now(),
+		}
+	}
+
+	for _, change := range diff.CommanderChanges {
+		if change.OldCommander.ID != "" {
+			events = append(events, hll.CommanderLeftRoleEvent{
+				GenericEvent: genericEvent(hll.EVENT_COMMANDER_LEFT_ROLE),
+				Team:         change.Team,
+				Player:       change.OldCommander.PlayerInfo,
+			})
+		}
+		if change.NewCommander.ID != "" {
+			events = append(events, hll.CommanderTookRoleEvent{
+				GenericEvent: genericEvent(hll.EVENT_COMMANDER_TOOK_ROLE),
+				Team:         change.Team,
+				Player:       change.NewCommander.PlayerInfo,
+			})
+		}
+	}
+
+	for _, squad := range diff.CreatedSquads {
+		events = append(events, hll.SquadCreatedEvent{
+			GenericEvent: genericEvent(hll.EVENT_SQUAD_CREATED),
+			Squad:        squad,
+		})
+	}
+
+	for _, squad := range diff.DisbandedSquads {
+		events = append(events, hll.SquadDisbandedEvent{
+			GenericEvent: genericEvent(hll.EVENT_SQUAD_DISBANDED),
+			Squad:        squad,
+		})
+	}
+
+	for _, change := range diff.LeaderChanges {
+		if change.NewLeader.ID == "" {
+			events = append(events, hll.SquadLeaderlessEvent{
+				GenericEvent: genericEvent(hll.EVENT_SQUAD_LEADERLESS),
+				Squad:        change.Squad,
+				OldLeader:    change.OldLeader.PlayerInfo,
+			})
+		} else {
+			events = append(events, hll.SquadLeaderChangedEvent{
+				GenericEvent: genericEvent(hll.EVENT_SQUAD_LEADER_CHANGED),
+				Squad:        change.Squad,
+				OldLeader:    change.OldLeader.PlayerInfo,
+				NewLeader:    change.NewLeader.PlayerInfo,
+			})
+		}
+	}
+
+	return events
+}
+
+var playerDiffEvents = []hll.EventType{
+	hll.EVENT_TEAM_SWITCHED,
+	hll.EVENT_SQUAD_SWITCHED,
+	hll.EVENT_ROLE_CHANGED,
+	hll.EVENT_LOADOUT_CHANGED,
+	hll.EVENT_SCORE_UPDATE,
+	hll.EVENT_POSITION_CHANGED,
+	hll.EVENT_PLAYER_SPAWNED,
+	hll.EVENT_PLAYER_DESPAWNED,
+	hll.EVENT_VEHICLE_DESTROYED,
+	hll.EVENT_VEHICLE_KILL,
+	hll.EVENT_LEVEL_UP,
+	hll.EVENT_CLAN_TAG_CHANGED,
+}
+
+func playerInfoDiffToEvents(oldData hll.DetailedPlayerInfo, newData hll.DetailedPlayerInfo, layer hll.Layer) []hll.Event {
 	events := []hll.Event{}
 
 	emptyPlayerInfo := hll.DetailedPlayerInfo{}
@@ -237,6 +627,75 @@ func playerInfoDiffToEvents(oldData hll.DetailedPlayerInfo, newData hll.Detailed
 			})
 		}
 	}
+	if !oldData.Position.IsActive() && newData.Position.IsActive() {
+		spawnEvent := hll.PlayerSpawnedEvent{
+			GenericEvent: hll.GenericEvent{
+				EventType: hll.EVENT_PLAYER_SPAWNED,
+				EventTime: time.Now(),
+			},
+			Player:        newData.PlayerInfo,
+			Position:      newData.Position,
+			GridReference: newData.Position.ToGridReference(),
+		}
+		if reference, err := layer.GridReference(newData.Position); err == nil {
+			spawnEvent.GridReference = reference.String()
+		}
+		if strongpoint, err := layer.NearestStrongpoint(newData.Position); err == nil {
+			spawnEvent.Strongpoint = &strongpoint
+		}
+		events = append(events, spawnEvent)
+	}
+	if oldData.Position.IsActive() && !newData.Position.IsActive() {
+		despawnEvent := hll.PlayerDespawnedEvent{
+			GenericEvent: hll.GenericEvent{
+				EventType: hll.EVENT_PLAYER_DESPAWNED,
+				EventTime: time.Now(),
+			},
+			Player:   newData.PlayerInfo,
+			Position: oldData.Position,
+			Reason:   hll.DESPAWN_REASON_REDEPLOY,
+		}
+		if killer := recentDeaths.Get(newData.ID); killer != nil {
+			despawnEvent.Reason = hll.DESPAWN_REASON_DEATH
+			despawnEvent.Killer = killer.Value()
+		} else if newData.Deaths > oldData.Deaths {
+			despawnEvent.Reason = hll.DESPAWN_REASON_DEATH
+		}
+		events = append(events, despawnEvent)
+	}
+	if newData.VehiclesDestroyed > oldData.VehiclesDestroyed {
+		events = append(events, hll.PlayerVehicleDestroyedEvent{
+			GenericEvent: hll.GenericEvent{
+				EventType: hll.EVENT_VEHICLE_DESTROYED,
+				EventTime: time.Now(),
+			},
+			Player:   newData.PlayerInfo,
+			OldCount: oldData.VehiclesDestroyed,
+			NewCount: newData.VehiclesDestroyed,
+		})
+	}
+	if newData.VehicleKills > oldData.VehicleKills {
+		events = append(events, hll.PlayerVehicleKillEvent{
+			GenericEvent: hll.GenericEvent{
+				EventType: hll.EVENT_VEHICLE_KILL,
+				EventTime: time.Now(),
+			},
+			Player:   newData.PlayerInfo,
+			OldCount: oldData.VehicleKills,
+			NewCount: newData.VehicleKills,
+		})
+	}
+	if oldData.Level != 0 && newData.Level > oldData.Level {
+		events = append(events, hll.PlayerLevelUpEvent{
+			GenericEvent: hll.GenericEvent{
+				EventType: hll.EVENT_LEVEL_UP,
+				EventTime: time.Now(),
+			},
+			Player:   newData.PlayerInfo,
+			OldLevel: oldData.Level,
+			NewLevel: newData.Level,
+		})
+	}
 	if oldData.ClanTag != newData.ClanTag {
 		events = append(events, hll.PlayerClanTagChangedEvent{
 			GenericEvent: hll.GenericEvent{
@@ -251,6 +710,51 @@ func playerInfoDiffToEvents(oldData hll.DetailedPlayerInfo, newData hll.Detailed
 	return events
 }
 
+// attach the latest known state of killer and victim to kill related events
+func enrichKillEvents(events []hll.Event) []hll.Event {
+	for i, event := range events {
+		switch e := event.(type) {
+		case hll.KillEvent:
+			e.Context = killContext(e.Killer, e.Victim)
+			events[i] = e
+		case hll.DeathEvent:
+			e.Context = killContext(e.Killer, e.Victim)
+			events[i] = e
+		case hll.TeamKillEvent:
+			e.Context = killContext(e.Killer, e.Victim)
+			events[i] = e
+		case hll.TeamDeathEvent:
+			e.Context = killContext(e.Killer, e.Victim)
+			events[i] = e
+		}
+	}
+	return events
+}
+
+func recordDeath(event hll.Event) {
+	switch e := event.(type) {
+	case hll.DeathEvent:
+		recentDeaths.Set(e.Victim.ID, e.Killer, ttlcache.DefaultTTL)
+	case hll.TeamDeathEvent:
+		recentDeaths.Set(e.Victim.ID, e.Killer, ttlcache.DefaultTTL)
+	}
+}
+
+func killContext(killer hll.PlayerInfo, victim hll.PlayerInfo) *hll.KillContext {
+	killerData, killerLive, err := getLastKnownPlayerInfo(killer.ID)
+	if err != nil {
+		return nil
+	}
+	victimData, victimLive, err := getLastKnownPlayerInfo(victim.ID)
+	if err != nil {
+		return nil
+	}
+	kc := hll.NewKillContext(killerData, victimData)
+	kc.KillerPositionLive = killerLive
+	kc.VictimPositionLive = victimLive
+	return kc
+}
+
 func getPlayerInfo(playerID string) (hll.DetailedPlayerInfo, error) {
 	pd := players.Get(playerID)
 	if pd == nil {
@@ -259,6 +763,26 @@ func getPlayerInfo(playerID string) (hll.DetailedPlayerInfo, error) {
 	return pd.Value(), nil
 }
 
+// same as getPlayerInfo, but an inactive position is replaced by the last active one,
+// since a dead player might already be despawned in the latest snapshot;
+// the flag tells whether the position is the one of the latest snapshot
+func getLastKnownPlayerInfo(playerID string) (hll.DetailedPlayerInfo, bool, error) {
+	pd, err := getPlayerInfo(playerID)
+	if err != nil {
+		return pd, false, err
+	}
+	if pd.Position.IsActive() {
+		return pd, true, nil
+	}
+	if pos := lastPositions.Get(playerID); pos != nil {
+		pd.Position = pos.Value()
+	}
+	return pd, false, nil
+}
+
 func setPlayerInfo(pd hll.DetailedPlayerInfo) {
 	players.Set(pd.ID, pd, ttlcache.DefaultTTL)
+	if pd.Position.IsActive() {
+		lastPositions.Set(pd.ID, pd.Position, ttlcache.DefaultTTL)
+	}
 }
@@ -0,0 +1,193 @@
+package rcon
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+const (
+	// a player missing from the player list is only considered gone after this period,
+	// which gives the disconnect log line the chance to close the session with the exact time
+	sessionGracePeriod = 10 * time.Second
+	// a player only known from the connect log may still be loading into the match
+	sessionUnseenTimeout = 5 * time.Minute
+)
+
+// merges the connect and disconnect logs with the presence in the player list into sessions,
+// the timeouts are measured with the polls only, since the log times come from the clock of the server
+type sessionTracker struct {
+	sessions map[string]*trackedSession
+	left     map[string]time.Time // the logged disconnects and the first poll after them, zero until then
+	mutex    sync.Mutex
+}
+
+type trackedSession struct {
+	session  hll.PlayerSession
+	team     hll.TeamIdentifier
+	role     hll.RoleIdentifier
+	lastSeen time.Time // the last time the player was in the player list, zero if never
+	noticed  time.Time // the first poll after the session was opened, zero until then
+}
+
+func newSessionTracker() *sessionTracker {
+	return &sessionTracker{
+		sessions: make(map[string]*trackedSession),
+		left:     make(map[string]time.Time),
+	}
+}
+
+// the running sessions ordered by their start
+func (st *sessionTracker) current() []hll.PlayerSession {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	sessions := []hll.PlayerSession{}
+	for _, ts := range st.sessions {
+		session := ts.session
+		session.TeamTime = maps.Clone(ts.session.TeamTime)
+		session.RoleTime = maps.Clone(ts.session.RoleTime)
+		sessions = append(sessions, session)
+	}
+	slices.SortFunc(sessions, func(a, b hll.PlayerSession) int {
+		if c := a.Start.Compare(b.Start); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Player.ID, b.Player.ID)
+	})
+	return sessions
+}
+
+func (st *sessionTracker) processLog(event hll.Event) []hll.Event {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	switch e := event.(type) {
+	case hll.ConnectEvent:
+		delete(st.left, e.Player.ID)
+		ts, ok := st.sessions[e.Player.ID]
+		if !ok {
+			st.sessions[e.Player.ID] = newTrackedSession(e.Player, e.EventTime)
+		} else if e.EventTime.Before(ts.session.Start) {
+			// the player list was faster than the log
+			ts.session.Start = e.EventTime
+		}
+	case hll.DisconnectEvent:
+		if _, ok := st.sessions[e.Player.ID]; ok {
+			st.left[e.Player.ID] = time.Time{}
+			return []hll.Event{st.close(e.Player.ID, e.EventTime, false)}
+		}
+	}
+	return []hll.Event{}
+}
+
+func (st *sessionTracker) processPlayers(players []hll.DetailedPlayerInfo, now time.Time) []hll.Event {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	for id, noticed := range st.left {
+		if noticed.IsZero() {
+			st.left[id] = now
+		} else if now.Sub(noticed) > sessionGracePeriod {
+			delete(st.left, id)
+		}
+	}
+
+	present := map[string]struct{}{}
+	for _, player := range players {
+		present[player.ID] = struct{}{}
+		ts, ok := st.sessions[player.ID]
+		if !ok {
+			if _, ok := st.left[player.ID]; ok {
+				// the player list lags behind the disconnect
+				continue
+			}
+			// the connect was missed, e.g. the player joined before the events were started
+			ts = newTrackedSession(player.PlayerInfo, now)
+			st.sessions[player.ID] = ts
+		}
+		ts.account(now)
+		ts.session.Player = player.PlayerInfo
+		ts.team = player.Team
+		ts.role = player.Role
+		ts.lastSeen = now
+	}
+
+	events := []hll.Event{}
+	for _, id := range slices.Sorted(maps.Keys(st.sessions)) {
+		if _, ok := present[id]; ok {
+			continue
+		}
+		ts := st.sessions[id]
+		if !ts.lastSeen.IsZero() {
+			if now.Sub(ts.lastSeen) > sessionGracePeriod {
+				events = append(events, st.close(id, ts.lastSeen, true))
+			}
+			continue
+		}
+
+		// only known from the log so far
+		if ts.noticed.IsZero() {
+			ts.noticed = now
+		} else if now.Sub(ts.noticed) > sessionUnseenTimeout {
+			events = append(events, st.close(id, ts.session.Start, true))
+		}
+	}
+	return events
+}
+
+func (st *sessionTracker) close(id string, end time.Time, inferred bool) hll.Event {
+	ts := st.sessions[id]
+	delete(st.sessions, id)
+
+	ts.account(end)
+	ts.session.End = end
+	if end.Before(ts.session.Start) {
+		ts.session.End = ts.session.Start
+	}
+	ts.session.Inferred = inferred
+	return hll.SessionClosedEvent{
+		GenericEvent: hll.GenericEvent{
+			EventType: hll.EVENT_SESSION_CLOSED,
+			EventTime: ts.session.End,
+		},
+		Session: ts.session,
+	}
+}
+
+func newTrackedSession(player hll.PlayerInfo, start time.Time) *trackedSession {
+	return &trackedSession{
+		session: hll.PlayerSession{
+			Player:   player,
+			Start:    start,
+			TeamTime: make(map[hll.TeamIdentifier]time.Duration),
+			RoleTime: make(map[hll.RoleIdentifier]time.Duration),
+		},
+	}
+}
+
+// credits the time since the player was last seen to the team and role of that time
+func (ts *trackedSession) account(now time.Time) {
+	if ts.lastSeen.IsZero() || !now.After(ts.lastSeen) {
+		return
+	}
+	elapsed := now.Sub(ts.lastSeen)
+	if ts.team != "" {
+		ts.session.TeamTime[ts.team] += elapsed
+	}
+	if ts.role != "" {
+		ts.session.RoleTime[ts.role] += elapsed
+	}
+	ts.lastSeen = now
+}
+
+func (r *Rcon) CurrentSessions() ([]hll.PlayerSession, error) {
+	if !r.Events.enabled {
+		return nil, errEventsDisabled
+	}
+	return r.Events.sessions.current(), nil
+}
@@ -0,0 +1,121 @@
+package rcon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestSessionTracker(t *testing.T) {
+	now := time.Unix(1639148969, 0)
+	alice := hll.PlayerInfo{Name: "Alice", ID: "1"}
+	bob := hll.PlayerInfo{Name: "Bob", ID: "2"}
+
+	listed := func(player hll.PlayerInfo, team hll.TeamIdentifier, role hll.RoleIdentifier) hll.DetailedPlayerInfo {
+		return hll.DetailedPlayerInfo{PlayerInfo: player, Team: team, Role: role}
+	}
+	connect := func(player hll.PlayerInfo, at time.Time) hll.Event {
+		return hll.ConnectEvent{GenericEvent: hll.GenericEvent{EventType: hll.EVENT_CONNECTED, EventTime: at}, Player: player}
+	}
+	disconnect := func(player hll.PlayerInfo, at time.Time) hll.Event {
+		return hll.DisconnectEvent{GenericEvent: hll.GenericEvent{EventType: hll.EVENT_DISCONNECTED, EventTime: at}, Player: player}
+	}
+	expectClosed := func(t *testing.T, events []hll.Event) hll.PlayerSession {
+		t.Helper()
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+		event, ok := events[0].(hll.SessionClosedEvent)
+		if !ok {
+			t.Fatalf("Expected SessionClosedEvent, but got %T", events[0])
+		}
+		return event.Session
+	}
+
+	t.Run("Logged sessions should track the time per team and role", func(t *testing.T) {
+		st := newSessionTracker()
+		st.processLog(connect(alice, now))
+		st.processPlayers([]hll.DetailedPlayerInfo{listed(alice, hll.TEAM_ALLIES, hll.ROLE_RIFLEMAN)}, now.Add(time.Minute))
+		st.processPlayers([]hll.DetailedPlayerInfo{listed(alice, hll.TEAM_ALLIES, hll.ROLE_MEDIC)}, now.Add(3*time.Minute))
+		st.processPlayers([]hll.DetailedPlayerInfo{listed(alice, hll.TEAM_AXIS, hll.ROLE_MEDIC)}, now.Add(4*time.Minute))
+
+		if sessions := st.current(); len(sessions) != 1 || !sessions[0].Active() {
+			t.Fatalf("Expected 1 running session, but got %v", sessions)
+		}
+
+		session := expectClosed(t, st.processLog(disconnect(alice, now.Add(10*time.Minute))))
+		if session.Inferred || session.Duration() != 10*time.Minute {
+			t.Errorf("Expected a logged session of 10m, but got %s (inferred %t)", session.Duration(), session.Inferred)
+		}
+		if session.TeamTime[hll.TEAM_ALLIES] != 3*time.Minute || session.TeamTime[hll.TEAM_AXIS] != 6*time.Minute {
+			t.Errorf("Expected 3m allies and 6m axis, but got %v", session.TeamTime)
+		}
+		if session.RoleTime[hll.ROLE_RIFLEMAN] != 2*time.Minute || session.RoleTime[hll.ROLE_MEDIC] != 7*time.Minute {
+			t.Errorf("Expected 2m rifleman and 7m medic, but got %v", session.RoleTime)
+		}
+		if sessions := st.current(); len(sessions) != 0 {
+			t.Errorf("Expected no running sessions, but got %v", sessions)
+		}
+	})
+
+	t.Run("A lagging player list should not reopen a logged disconnect", func(t *testing.T) {
+		st := newSessionTracker()
+		st.processPlayers([]hll.DetailedPlayerInfo{listed(alice, hll.TEAM_ALLIES, hll.ROLE_RIFLEMAN)}, now)
+		expectClosed(t, st.processLog(disconnect(alice, now.Add(time.Minute))))
+
+		if events := st.processPlayers([]hll.DetailedPlayerInfo{listed(alice, hll.TEAM_ALLIES, hll.ROLE_RIFLEMAN)}, now.Add(time.Minute+time.Second)); len(events) != 0 {
+			t.Errorf("Expected no events, but got %v", events)
+		}
+		if sessions := st.current(); len(sessions) != 0 {
+			t.Errorf("Expected no running sessions, but got %v", sessions)
+		}
+	})
+
+	t.Run("A missed disconnect should be closed after the grace period", func(t *testing.T) {
+		st := newSessionTracker()
+		st.processPlayers([]hll.DetailedPlayerInfo{listed(alice, hll.TEAM_ALLIES, hll.ROLE_RIFLEMAN), listed(bob, hll.TEAM_AXIS, hll.ROLE_SNIPER)}, now)
+		st.processPlayers([]hll.DetailedPlayerInfo{listed(alice, hll.TEAM_ALLIES, hll.ROLE_RIFLEMAN), listed(bob, hll.TEAM_AXIS, hll.ROLE_SNIPER)}, now.Add(time.Minute))
+
+		if events := st.processPlayers([]hll.DetailedPlayerInfo{listed(bob, hll.TEAM_AXIS, hll.ROLE_SNIPER)}, now.Add(time.Minute+sessionGracePeriod)); len(events) != 0 {
+			t.Errorf("Expected no events within the grace period, but got %v", events)
+		}
+
+		session := expectClosed(t, st.processPlayers([]hll.DetailedPlayerInfo{listed(bob, hll.TEAM_AXIS, hll.ROLE_SNIPER)}, now.Add(2*time.Minute)))
+		if session.Player.ID != alice.ID || !session.Inferred {
+			t.Errorf("Expected the inferred session of alice, but got %v", session)
+		}
+		if !session.End.Equal(now.Add(time.Minute)) || session.TeamTime[hll.TEAM_ALLIES] != time.Minute {
+			t.Errorf("Expected the session to end when alice was last seen, but got %v", session.End)
+		}
+	})
+
+	t.Run("A player still loading should not be closed after the grace period", func(t *testing.T) {
+		st := newSessionTracker()
+		// the clock of the server is a minute behind the client
+		st.processLog(connect(alice, now.Add(-time.Minute)))
+
+		if events := st.processPlayers([]hll.DetailedPlayerInfo{}, now); len(events) != 0 {
+			t.Errorf("Expected no events right after the connect, but got %v", events)
+		}
+		if events := st.processPlayers([]hll.DetailedPlayerInfo{}, now.Add(time.Minute)); len(events) != 0 {
+			t.Errorf("Expected no events while the player is loading, but got %v", events)
+		}
+
+		session := expectClosed(t, st.processPlayers([]hll.DetailedPlayerInfo{}, now.Add(time.Second+sessionUnseenTimeout)))
+		if session.Player.ID != alice.ID || !session.Inferred || session.Duration() != 0 {
+			t.Errorf("Expected an empty inferred session of alice, but got %v", session)
+		}
+	})
+
+	t.Run("A connect after the first listing should move the start back", func(t *testing.T) {
+		st := newSessionTracker()
+		st.processPlayers([]hll.DetailedPlayerInfo{listed(alice, hll.TEAM_ALLIES, hll.ROLE_RIFLEMAN)}, now)
+		st.processLog(connect(alice, now.Add(-time.Second)))
+
+		sessions := st.current()
+		if len(sessions) != 1 || !sessions[0].Start.Equal(now.Add(-time.Second)) {
+			t.Errorf("Expected 1 session started at %v, but got %v", now.Add(-time.Second), sessions)
+		}
+	})
+}
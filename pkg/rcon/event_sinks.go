@@ -0,0 +1,80 @@
+package rcon
+
+import (
+	"io"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+	"github.com/zMoooooritz/go-let-loose/pkg/logger"
+)
+
+// a sink forwards events to an outside system, the EventJournal and the WebhookSink are sinks as well
+type EventSink interface {
+	Write(event hll.Event) error
+	Close() error
+}
+
+// decides whether an event is passed to a sink
+type EventFilter func(hll.Event) bool
+
+func EventTypeFilter(eventTypes ...hll.EventType) EventFilter {
+	return func(event hll.Event) bool {
+		return slices.Contains(eventTypes, event.Type())
+	}
+}
+
+type sinkObserver struct {
+	sink   EventSink
+	filter EventFilter
+}
+
+func (so *sinkObserver) Notify(event hll.Event) {
+	if so.filter != nil && !so.filter(event) {
+		return
+	}
+	if err := so.sink.Write(event); err != nil {
+		logger.Error("writing event to sink failed", err)
+	}
+}
+
+// passes all events accepted by the filter to the sink, all events are passed if the filter is nil;
+// the sink is not closed by the Rcon
+func (r *Rcon) AddSink(sink EventSink, filter EventFilter) error {
+	if !r.Events.enabled {
+		return errEventsDisabled
+	}
+	r.Events.Register(&sinkObserver{sink: sink, filter: filter})
+	return nil
+}
+
+// writes every event as one line of JSON
+type JSONSink struct {
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+func NewJSONSink(writer io.Writer) *JSONSink {
+	return &JSONSink{writer: writer}
+}
+
+func NewStdoutSink() *JSONSink {
+	return NewJSONSink(os.Stdout)
+}
+
+func (js *JSONSink) Write(event hll.Event) error {
+	data, err := hll.MarshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	_, err = js.writer.Write(append(data, '\n'))
+	return err
+}
+
+func (js *JSONSink) Close() error {
+	return nil
+}
@@ -0,0 +1,114 @@
+package rcon
+
+import (
+	"slices"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+type StreakConfig struct {
+	KillStreakThresholds []int         // a KillStreakEvent is emitted once a streak reaches one of these values
+	MultiKillThresholds  []int         // a MultiKillEvent is emitted once the kills within the window reach one of these values
+	MultiKillWindow      time.Duration // kills of a player within this window count towards a multi kill
+}
+
+func DefaultStreakConfig() StreakConfig {
+	return StreakConfig{
+		KillStreakThresholds: []int{5, 10, 15, 20, 25, 30},
+		MultiKillThresholds:  []int{2, 3, 4, 5},
+		MultiKillWindow:      10 * time.Second,
+	}
+}
+
+type streakTracker struct {
+	config      StreakConfig
+	streaks     map[string]int
+	recentKills map[string][]hll.KillEvent
+}
+
+func newStreakTracker(config StreakConfig) *streakTracker {
+	return &streakTracker{
+		config:      config,
+		streaks:     make(map[string]int),
+		recentKills: make(map[string][]hll.KillEvent),
+	}
+}
+
+func (st *streakTracker) process(event hll.Event) []hll.Event {
+	events := []hll.Event{}
+
+	switch e := event.(type) {
+	case hll.KillEvent:
+		st.streaks[e.Killer.ID]++
+		streak := st.streaks[e.Killer.ID]
+		if slices.Contains(st.config.KillStreakThresholds, streak) {
+			events = append(events, hll.KillStreakEvent{
+				GenericEvent: hll.GenericEvent{
+					EventType: hll.EVENT_KILL_STREAK,
+					EventTime: e.EventTime,
+				},
+				Player: e.Killer,
+				Streak: streak,
+			})
+		}
+
+		kills := []hll.KillEvent{}
+		for _, kill := range st.recentKills[e.Killer.ID] {
+			if e.EventTime.Sub(kill.EventTime) <= st.config.MultiKillWindow {
+				kills = append(kills, kill)
+			}
+		}
+		kills = append(kills, e)
+		st.recentKills[e.Killer.ID] = kills
+		if slices.Contains(st.config.MultiKillThresholds, len(kills)) {
+			victims := []hll.PlayerInfo{}
+			for _, kill := range kills {
+				victims = append(victims, kill.Victim)
+			}
+			events = append(events, hll.MultiKillEvent{
+				GenericEvent: hll.GenericEvent{
+					EventType: hll.EVENT_MULTI_KILL,
+					EventTime: e.EventTime,
+				},
+				Player:  e.Killer,
+				Kills:   len(kills),
+				Window:  e.EventTime.Sub(kills[0].EventTime),
+				Victims: victims,
+			})
+		}
+	case hll.DeathEvent:
+		events = append(events, st.endStreak(e.Victim, e.Killer, e.EventTime)...)
+	case hll.TeamDeathEvent:
+		events = append(events, st.endStreak(e.Victim, e.Killer, e.EventTime)...)
+	case hll.DisconnectEvent:
+		delete(st.streaks, e.Player.ID)
+		delete(st.recentKills, e.Player.ID)
+	case hll.MatchStartEvent, hll.MatchEndEvent:
+		st.streaks = make(map[string]int)
+		st.recentKills = make(map[string][]hll.KillEvent)
+	}
+
+	return events
+}
+
+func (st *streakTracker) endStreak(victim hll.PlayerInfo, killer hll.PlayerInfo, eventTime time.Time) []hll.Event {
+	events := []hll.Event{}
+
+	streak := st.streaks[victim.ID]
+	delete(st.streaks, victim.ID)
+	delete(st.recentKills, victim.ID)
+
+	if len(st.config.KillStreakThresholds) > 0 && streak >= slices.Min(st.config.KillStreakThresholds) {
+		events = append(events, hll.KillStreakEndedEvent{
+			GenericEvent: hll.GenericEvent{
+				EventType: hll.EVENT_KILL_STREAK_ENDED,
+				EventTime: eventTime,
+			},
+			Player:  victim,
+			Streak:  streak,
+			EndedBy: killer,
+		})
+	}
+	return events
+}
@@ -0,0 +1,89 @@
+package rcon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestStreakTracker(t *testing.T) {
+	start := time.Unix(1639143555, 0)
+	killer := hll.PlayerInfo{Name: "Killer", ID: "1"}
+	victim := hll.PlayerInfo{Name: "Victim", ID: "2"}
+
+	kill := func(offset time.Duration) hll.KillEvent {
+		return hll.KillEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_KILL, EventTime: start.Add(offset)},
+			Killer:       killer,
+			Victim:       victim,
+		}
+	}
+
+	t.Run("Kill streak thresholds should emit KillStreakEvent", func(t *testing.T) {
+		st := newStreakTracker(StreakConfig{KillStreakThresholds: []int{3}})
+
+		streakEvents := []hll.Event{}
+		for i := range 4 {
+			streakEvents = append(streakEvents, st.process(kill(time.Duration(i)*time.Minute))...)
+		}
+		if len(streakEvents) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(streakEvents))
+		}
+
+		event, ok := streakEvents[0].(hll.KillStreakEvent)
+		if !ok {
+			t.Fatalf("Expected KillStreakEvent, but got %T", streakEvents[0])
+		}
+		if event.Player != killer || event.Streak != 3 {
+			t.Errorf("Expected streak of 3 for %v, but got %d for %v", killer, event.Streak, event.Player)
+		}
+	})
+
+	t.Run("Death should end the streak", func(t *testing.T) {
+		st := newStreakTracker(StreakConfig{KillStreakThresholds: []int{2}})
+		st.process(kill(0))
+		st.process(kill(time.Minute))
+
+		events := st.process(hll.DeathEvent{Victim: killer, Killer: victim})
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+
+		event, ok := events[0].(hll.KillStreakEndedEvent)
+		if !ok {
+			t.Fatalf("Expected KillStreakEndedEvent, but got %T", events[0])
+		}
+		if event.Streak != 2 || event.EndedBy != victim {
+			t.Errorf("Expected streak of 2 ended by %v, but got %d ended by %v", victim, event.Streak, event.EndedBy)
+		}
+
+		if events := st.process(kill(2 * time.Minute)); len(events) != 0 {
+			t.Errorf("Expected a new streak without events, but got %v", events)
+		}
+	})
+
+	t.Run("Kills within the window should emit MultiKillEvent", func(t *testing.T) {
+		st := newStreakTracker(StreakConfig{MultiKillThresholds: []int{2, 3}, MultiKillWindow: 5 * time.Second})
+
+		if events := st.process(kill(0)); len(events) != 0 {
+			t.Fatalf("Expected no events, but got %v", events)
+		}
+		if events := st.process(kill(10 * time.Second)); len(events) != 0 {
+			t.Fatalf("Expected no events outside of the window, but got %v", events)
+		}
+
+		events := st.process(kill(12 * time.Second))
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+
+		event, ok := events[0].(hll.MultiKillEvent)
+		if !ok {
+			t.Fatalf("Expected MultiKillEvent, but got %T", events[0])
+		}
+		if event.Kills != 2 || event.Window != 2*time.Second || len(event.Victims) != 2 {
+			t.Errorf("Expected 2 kills within 2s, but got %d kills within %v", event.Kills, event.Window)
+		}
+	})
+}
@@ -0,0 +1,270 @@
+package rcon
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+// keeps track of the players inside the active strongpoints and the enemy home sectors of the current layer
+type strongpointTracker struct {
+	layer   hll.LayerIdentifier
+	points  []*trackedStrongpoint
+	inEnemy map[string]hll.TeamIdentifier // the players inside the home sector of the enemy team
+	mutex   sync.Mutex
+}
+
+type trackedStrongpoint struct {
+	strongpoint hll.Strongpoint
+	sectorIndex int
+	inside      map[string]hll.DetailedPlayerInfo
+}
+
+func newStrongpointTracker() *strongpointTracker {
+	return &strongpointTracker{inEnemy: make(map[string]hll.TeamIdentifier)}
+}
+
+// the presence in all active strongpoints ordered by sector
+func (st *strongpointTracker) current() []hll.StrongpointPresence {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	presence := []hll.StrongpointPresence{}
+	for _, point := range st.points {
+		presence = append(presence, point.presence())
+	}
+	return presence
+}
+
+func (st *strongpointTracker) process(layer hll.Layer, layout []string, players []hll.DetailedPlayerInfo, now time.Time) []hll.Event {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	events := []hll.Event{}
+	active := activeStrongpoints(layer, layout)
+	if layer.ID == st.layer {
+		// the layout may get known during the match, the players inside stay the same
+		for _, previous := range st.points {
+			kept := false
+			for _, point := range active {
+				if previous.strongpoint.ID == point.strongpoint.ID {
+					point.inside = previous.inside
+					kept = true
+				}
+			}
+			if !kept {
+				// the strongpoint is not part of the layout, the players inside left it
+				events = append(events, previous.leaveAll(now)...)
+			}
+		}
+	} else {
+		// on a new layer the players of the previous one did not leave, the match is over
+		st.inEnemy = make(map[string]hll.TeamIdentifier)
+	}
+	st.layer = layer.ID
+	st.points = active
+
+	for _, point := range st.points {
+		entered := []hll.DetailedPlayerInfo{}
+		left := []hll.DetailedPlayerInfo{}
+
+		inside := make(map[string]hll.DetailedPlayerInfo)
+		for _, player := range players {
+			if player.Team == hll.TEAM_NONE || !player.IsSpawned() || !point.strongpoint.IsInside(player.Position) {
+				continue
+			}
+			inside[player.ID] = player
+			if _, ok := point.inside[player.ID]; !ok {
+				entered = append(entered, player)
+			}
+		}
+		for id, player := range point.inside {
+			if _, ok := inside[id]; !ok {
+				left = append(left, player)
+			}
+		}
+		slices.SortFunc(left, func(a, b hll.DetailedPlayerInfo) int { return strings.Compare(a.ID, b.ID) })
+		point.inside = inside
+
+		counts := point.presence().Counts()
+		for _, player := range left {
+			events = append(events, hll.StrongpointLeftEvent{
+				GenericEvent: hll.GenericEvent{
+					EventType: hll.EVENT_STRONGPOINT_LEFT,
+					EventTime: now,
+				},
+				Player:      player.PlayerInfo,
+				Team:        player.Team,
+				Strongpoint: point.strongpoint,
+				SectorIndex: point.sectorIndex,
+				Counts:      counts,
+			})
+		}
+		for _, player := range entered {
+			events = append(events, hll.StrongpointEnteredEvent{
+				GenericEvent: hll.GenericEvent{
+					EventType: hll.EVENT_STRONGPOINT_ENTERED,
+					EventTime: now,
+				},
+				Player:      player.PlayerInfo,
+				Team:        player.Team,
+				Strongpoint: point.strongpoint,
+				SectorIndex: point.sectorIndex,
+				Counts:      counts,
+			})
+		}
+	}
+	return append(events, st.processEnemyHQ(layer, players, now)...)
+}
+
+func (st *strongpointTracker) processEnemyHQ(layer hll.Layer, players []hll.DetailedPlayerInfo, now time.Time) []hll.Event {
+	sectors, err := layer.OrderedSectors()
+	if err != nil || len(sectors) == 0 {
+		return []hll.Event{}
+	}
+
+	entered := []hll.DetailedPlayerInfo{}
+	inEnemy := make(map[string]hll.TeamIdentifier)
+	counts := hll.TeamData{}
+	for _, player := range players {
+		if player.Team == hll.TEAM_NONE || !player.IsSpawned() {
+			continue
+		}
+		index := enemyHomeSector(layer, player.Team, len(sectors))
+		if !isInSector(layer, sectors[index], player.Position) {
+			continue
+		}
+		inEnemy[player.ID] = player.Team
+		if player.Team == hll.TEAM_ALLIES {
+			counts.Allies++
+		} else {
+			counts.Axis++
+		}
+		if _, ok := st.inEnemy[player.ID]; !ok {
+			entered = append(entered, player)
+		}
+	}
+	st.inEnemy = inEnemy
+
+	events := []hll.Event{}
+	for _, player := range entered {
+		count := counts.Allies
+		if player.Team == hll.TEAM_AXIS {
+			count = counts.Axis
+		}
+		events = append(events, hll.EnemyHQEnteredEvent{
+			GenericEvent: hll.GenericEvent{
+				EventType: hll.EVENT_ENEMY_HQ_ENTERED,
+				EventTime: now,
+			},
+			Player:      player.PlayerInfo,
+			Team:        player.Team,
+			SectorIndex: enemyHomeSector(layer, player.Team, len(sectors)),
+			Position:    player.Position,
+			Count:       count,
+		})
+	}
+	return events
+}
+
+// the index in Layer.OrderedSectors of the outermost sector on the start side of the enemy team
+func enemyHomeSector(layer hll.Layer, team hll.TeamIdentifier, sectorCount int) int {
+	// by default the allies start left/top, so the axis players find the enemy HQ at the start of the list
+	if (team == hll.TEAM_AXIS) != layer.MapIdentifier.Map().MirroredFactions {
+		return 0
+	}
+	return sectorCount - 1
+}
+
+func isInSector(layer hll.Layer, sector hll.Sector, pos hll.Position) bool {
+	minX, minY := layer.Grid.GridToWorldMin(sector.From)
+	maxX, maxY := layer.Grid.GridToWorldMax(sector.To)
+	return pos.X >= minX && pos.X < maxX && pos.Y >= minY && pos.Y < maxY
+}
+
+func (tsp *trackedStrongpoint) leaveAll(now time.Time) []hll.Event {
+	left := []hll.DetailedPlayerInfo{}
+	for _, player := range tsp.inside {
+		left = append(left, player)
+	}
+	slices.SortFunc(left, func(a, b hll.DetailedPlayerInfo) int { return strings.Compare(a.ID, b.ID) })
+	tsp.inside = make(map[string]hll.DetailedPlayerInfo)
+
+	events := []hll.Event{}
+	for _, player := range left {
+		events = append(events, hll.StrongpointLeftEvent{
+			GenericEvent: hll.GenericEvent{
+				EventType: hll.EVENT_STRONGPOINT_LEFT,
+				EventTime: now,
+			},
+			Player:      player.PlayerInfo,
+			Team:        player.Team,
+			Strongpoint: tsp.strongpoint,
+			SectorIndex: tsp.sectorIndex,
+			Counts:      hll.TeamData{},
+		})
+	}
+	return events
+}
+
+func (tsp *trackedStrongpoint) presence() hll.StrongpointPresence {
+	presence := hll.StrongpointPresence{
+		Strongpoint: tsp.strongpoint,
+		SectorIndex: tsp.sectorIndex,
+		Allies:      []hll.PlayerInfo{},
+		Axis:        []hll.PlayerInfo{},
+	}
+	for _, player := range tsp.inside {
+		if player.Team == hll.TEAM_ALLIES {
+			presence.Allies = append(presence.Allies, player.PlayerInfo)
+		} else {
+			presence.Axis = append(presence.Axis, player.PlayerInfo)
+		}
+	}
+	byID := func(a, b hll.PlayerInfo) int { return strings.Compare(a.ID, b.ID) }
+	slices.SortFunc(presence.Allies, byID)
+	slices.SortFunc(presence.Axis, byID)
+	return presence
+}
+
+// the strongpoints of the layout, all strongpoints of a sector if its active one is unknown
+func activeStrongpoints(layer hll.Layer, layout []string) []*trackedStrongpoint {
+	sectors, err := layer.OrderedSectors()
+	if err != nil {
+		return []*trackedStrongpoint{}
+	}
+
+	points := []*trackedStrongpoint{}
+	for index, sector := range sectors {
+		candidates := []hll.Strongpoint{}
+		for _, objective := range layout {
+			if strongpoint, ok := sector.StrongpointByName(objective); ok {
+				candidates = []hll.Strongpoint{strongpoint}
+				break
+			}
+		}
+		if len(candidates) == 0 {
+			for _, zone := range sector.CaptureZones {
+				candidates = append(candidates, zone.Strongpoint)
+			}
+		}
+		for _, strongpoint := range candidates {
+			points = append(points, &trackedStrongpoint{
+				strongpoint: strongpoint,
+				sectorIndex: index,
+				inside:      make(map[string]hll.DetailedPlayerInfo),
+			})
+		}
+	}
+	return points
+}
+
+func (r *Rcon) StrongpointPresence() ([]hll.StrongpointPresence, error) {
+	if !r.Events.enabled {
+		return nil, errEventsDisabled
+	}
+	return r.Events.strongpoints.current(), nil
+}
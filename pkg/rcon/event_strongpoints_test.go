@@ -0,0 +1,162 @@
+package rcon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestStrongpointTracker(t *testing.T) {
+	now := time.Unix(1639148969, 0)
+	layer := hll.LAYER_CARENTAN_WARFARE.Layer()
+	// the center of BLACTOT in the first sector
+	blactot := hll.Position{X: -65543.41, Y: -39731.965, Z: 1359.9531}
+	outside := hll.Position{X: 95000, Y: 95000, Z: 1000}
+
+	player := func(id string, team hll.TeamIdentifier, pos hll.Position) hll.DetailedPlayerInfo {
+		return hll.DetailedPlayerInfo{PlayerInfo: hll.PlayerInfo{Name: id, ID: id}, Team: team, Position: pos}
+	}
+	presenceOf := func(t *testing.T, st *strongpointTracker, id string) hll.StrongpointPresence {
+		t.Helper()
+		for _, presence := range st.current() {
+			if presence.Strongpoint.ID == id {
+				return presence
+			}
+		}
+		t.Fatalf("Expected strongpoint %s to be tracked", id)
+		return hll.StrongpointPresence{}
+	}
+
+	t.Run("Players moving in and out should emit events", func(t *testing.T) {
+		st := newStrongpointTracker()
+		events := st.process(layer, []string{}, []hll.DetailedPlayerInfo{
+			player("1", hll.TEAM_ALLIES, blactot),
+			player("2", hll.TEAM_AXIS, blactot),
+			player("3", hll.TEAM_AXIS, outside),
+		}, now)
+		// BLACTOT lies in the home sector of the allies, the axis player entered the enemy HQ as well
+		if len(events) != 3 {
+			t.Fatalf("Expected 3 events, but got %d", len(events))
+		}
+		if _, ok := events[2].(hll.EnemyHQEnteredEvent); !ok {
+			t.Errorf("Expected EnemyHQEnteredEvent, but got %T", events[2])
+		}
+		entered, ok := events[1].(hll.StrongpointEnteredEvent)
+		if !ok {
+			t.Fatalf("Expected StrongpointEnteredEvent, but got %T", events[1])
+		}
+		if entered.Strongpoint.ID != "BLACTOT" || entered.SectorIndex != 0 || entered.Counts != (hll.TeamData{Allies: 1, Axis: 1}) {
+			t.Errorf("Expected BLACTOT in sector 0 with 1:1 players, but got %s in %d with %v", entered.Strongpoint.ID, entered.SectorIndex, entered.Counts)
+		}
+		if presence := presenceOf(t, st, "BLACTOT"); !presence.IsContested() {
+			t.Errorf("Expected BLACTOT to be contested, but got %v", presence.Counts())
+		}
+
+		// the axis player died
+		events = st.process(layer, []string{}, []hll.DetailedPlayerInfo{
+			player("1", hll.TEAM_ALLIES, blactot),
+			player("2", hll.TEAM_AXIS, hll.Position{}),
+		}, now.Add(time.Second))
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+		left, ok := events[0].(hll.StrongpointLeftEvent)
+		if !ok {
+			t.Fatalf("Expected StrongpointLeftEvent, but got %T", events[0])
+		}
+		if left.Player.ID != "2" || left.Team != hll.TEAM_AXIS || left.Counts != (hll.TeamData{Allies: 1}) {
+			t.Errorf("Expected player 2 of axis to leave with 1:0 players, but got %s of %s with %v", left.Player.ID, left.Team, left.Counts)
+		}
+
+		if events := st.process(layer, []string{}, []hll.DetailedPlayerInfo{player("1", hll.TEAM_ALLIES, blactot)}, now.Add(2*time.Second)); len(events) != 0 {
+			t.Errorf("Expected no events, but got %v", events)
+		}
+	})
+
+	t.Run("A known layout should restrict the tracked strongpoints", func(t *testing.T) {
+		st := newStrongpointTracker()
+		st.process(layer, []string{}, []hll.DetailedPlayerInfo{player("1", hll.TEAM_ALLIES, blactot)}, now)
+		if count := len(st.current()); count != len(layer.Strongpoints()) {
+			t.Errorf("Expected all %d strongpoints to be tracked, but got %d", len(layer.Strongpoints()), count)
+		}
+
+		sectors, _ := layer.OrderedSectors()
+		layout := []string{"Blactot"}
+		for _, sector := range sectors[1:] {
+			layout = append(layout, sector.CaptureZones[0].Strongpoint.ID)
+		}
+		// the player inside stays inside when the layout gets known
+		if events := st.process(layer, layout, []hll.DetailedPlayerInfo{player("1", hll.TEAM_ALLIES, blactot)}, now.Add(time.Second)); len(events) != 0 {
+			t.Errorf("Expected no events, but got %v", events)
+		}
+		if count := len(st.current()); count != len(sectors) {
+			t.Errorf("Expected %d strongpoints to be tracked, but got %d", len(sectors), count)
+		}
+		if presence := presenceOf(t, st, "BLACTOT"); presence.Counts().Allies != 1 {
+			t.Errorf("Expected 1 allied player in BLACTOT, but got %v", presence.Counts())
+		}
+	})
+	t.Run("Players inside dropped strongpoints should leave them", func(t *testing.T) {
+		st := newStrongpointTracker()
+		// the center of 502ND START, also in the first sector
+		start := hll.Position{X: -67076.41, Y: 4670.035, Z: 123.953125}
+		st.process(layer, []string{}, []hll.DetailedPlayerInfo{player("1", hll.TEAM_ALLIES, blactot), player("2", hll.TEAM_AXIS, start)}, now)
+
+		events := st.process(layer, []string{"Blactot"}, []hll.DetailedPlayerInfo{player("1", hll.TEAM_ALLIES, blactot), player("2", hll.TEAM_AXIS, start)}, now.Add(time.Second))
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+		left, ok := events[0].(hll.StrongpointLeftEvent)
+		if !ok {
+			t.Fatalf("Expected StrongpointLeftEvent, but got %T", events[0])
+		}
+		if left.Player.ID != "2" || left.Strongpoint.ID != "502ND START" || left.Counts != (hll.TeamData{}) {
+			t.Errorf("Expected player 2 to leave 502ND START with 0:0 players, but got %s leaving %s with %v", left.Player.ID, left.Strongpoint.ID, left.Counts)
+		}
+	})
+
+	t.Run("Players entering the enemy home sector should emit events once", func(t *testing.T) {
+		st := newStrongpointTracker()
+		// the home sectors of Carentan are the outermost columns, allies start left
+		axisHQ := hll.Position{X: 78213.5, Y: 12405.25, Z: 410.5}
+		alliedHQ := hll.Position{X: -90000, Y: 12405.25, Z: 410.5}
+		events := st.process(layer, []string{}, []hll.DetailedPlayerInfo{
+			player("1", hll.TEAM_ALLIES, axisHQ),
+			player("2", hll.TEAM_ALLIES, axisHQ),
+			player("3", hll.TEAM_AXIS, axisHQ),
+			player("4", hll.TEAM_ALLIES, alliedHQ),
+		}, now)
+		entered := []hll.EnemyHQEnteredEvent{}
+		for _, event := range events {
+			if event, ok := event.(hll.EnemyHQEnteredEvent); ok {
+				entered = append(entered, event)
+			}
+		}
+		if len(entered) != 2 {
+			t.Fatalf("Expected 2 events, but got %d", len(entered))
+		}
+		if entered[0].Player.ID != "1" || entered[0].Team != hll.TEAM_ALLIES || entered[0].SectorIndex != 4 || entered[0].Count != 2 {
+			t.Errorf("Expected player 1 of allies in sector 4 with 2 players, but got %s of %s in %d with %d", entered[0].Player.ID, entered[0].Team, entered[0].SectorIndex, entered[0].Count)
+		}
+
+		// staying inside does not emit again, dying and respawning there does
+		events = st.process(layer, []string{}, []hll.DetailedPlayerInfo{
+			player("1", hll.TEAM_ALLIES, axisHQ),
+			player("2", hll.TEAM_ALLIES, hll.Position{}),
+		}, now.Add(time.Second))
+		if len(events) != 0 {
+			t.Errorf("Expected no events, but got %v", events)
+		}
+		events = st.process(layer, []string{}, []hll.DetailedPlayerInfo{
+			player("1", hll.TEAM_ALLIES, axisHQ),
+			player("2", hll.TEAM_ALLIES, axisHQ),
+		}, now.Add(2*time.Second))
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+		if event, ok := events[0].(hll.EnemyHQEnteredEvent); !ok || event.Player.ID != "2" {
+			t.Errorf("Expected player 2 to enter the enemy HQ, but got %v", events[0])
+		}
+	})
+}
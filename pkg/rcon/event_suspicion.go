@@ -0,0 +1,202 @@
+package rcon
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+const (
+	// the minimal amount of players with kills within the window for a meaningful server baseline
+	suspicionMinKillers = 3
+	// picked up enemy weapons are legit, so this only adds to other evidence and stays below the default threshold
+	foreignWeaponScore = 0.5
+)
+
+type SuspicionConfig struct {
+	MaxDistances map[hll.WeaponType]float64 // kills beyond this distance in meters are evidence, the score grows with the distance
+	RateWindow   time.Duration              // the kill rates are measured over this window, no kill rate evidence if not positive
+	RateFactor   float64                    // a kill rate this many times the server baseline is evidence
+	MinRateKills int                        // the kills a player needs within the window before the kill rate is evidence
+	Threshold    float64                    // a SuspiciousKillEvent is emitted once the summed up score of a kill reaches this value
+}
+
+func DefaultSuspicionConfig() SuspicionConfig {
+	return SuspicionConfig{
+		MaxDistances: map[hll.WeaponType]float64{
+			hll.WEAPON_TYPE_SUBMACHINE_GUN: 100,
+			hll.WEAPON_TYPE_PISTOL:         60,
+			hll.WEAPON_TYPE_REVOLVER:       60,
+			hll.WEAPON_TYPE_SHOTGUN:        50,
+			hll.WEAPON_TYPE_FLAMETHROWER:   40,
+			hll.WEAPON_TYPE_MELEE:          10,
+		},
+		RateWindow:   5 * time.Minute,
+		RateFactor:   4,
+		MinRateKills: 10,
+		Threshold:    1,
+	}
+}
+
+// the distances of the kills with one weapon type
+type KillDistanceStats struct {
+	Kills int
+	Total float64 // in meters
+	Max   float64 // in meters
+}
+
+func (kds KillDistanceStats) Average() float64 {
+	if kds.Kills == 0 {
+		return 0
+	}
+	return kds.Total / float64(kds.Kills)
+}
+
+type suspicionKill struct {
+	time   time.Time
+	killer string
+}
+
+// scores every kill by the distance, the weapon and the kill rate of the killer
+type suspicionTracker struct {
+	config    SuspicionConfig
+	recent    []suspicionKill
+	distances map[hll.WeaponType]KillDistanceStats
+	mutex     sync.Mutex
+}
+
+func newSuspicionTracker(config SuspicionConfig) *suspicionTracker {
+	return &suspicionTracker{
+		config:    config,
+		recent:    []suspicionKill{},
+		distances: make(map[hll.WeaponType]KillDistanceStats),
+	}
+}
+
+// the kill distances per weapon type of the current match
+func (st *suspicionTracker) current() map[hll.WeaponType]KillDistanceStats {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	return maps.Clone(st.distances)
+}
+
+func (st *suspicionTracker) process(event hll.Event) []hll.Event {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	switch e := event.(type) {
+	case hll.KillEvent:
+		evidence := []hll.SuspicionEvidence{}
+		distance := 0.0
+		if e.Context != nil && e.Context.Distance > 0 {
+			distance = e.Context.Distance
+			stats := st.distances[e.Weapon.Type]
+			stats.Kills++
+			stats.Total += distance
+			stats.Max = max(stats.Max, distance)
+			st.distances[e.Weapon.Type] = stats
+
+			// a last known position may be up to minutes old, only live positions give a reliable distance
+			live := e.Context.KillerPositionLive && e.Context.VictimPositionLive
+			if limit, ok := st.config.MaxDistances[e.Weapon.Type]; ok && limit > 0 && distance > limit && live {
+				evidence = append(evidence, hll.SuspicionEvidence{
+					Kind:   hll.SUSPICION_LONG_RANGE,
+					Score:  distance / limit,
+					Detail: fmt.Sprintf("%s kill at %.0fm, expected at most %.0fm", e.Weapon.Type, distance, limit),
+				})
+			}
+		}
+		if e.Context != nil {
+			faction := e.Context.Killer.Faction
+			if faction != "" && faction != hll.FACTION_UNASSIGNED && len(e.Weapon.Factions) > 0 && !slices.Contains(e.Weapon.Factions, faction) {
+				evidence = append(evidence, hll.SuspicionEvidence{
+					Kind:   hll.SUSPICION_FOREIGN_WEAPON,
+					Score:  foreignWeaponScore,
+					Detail: fmt.Sprintf("%s used by %s, available to %v", e.Weapon.Name, faction, e.Weapon.Factions),
+				})
+			}
+		}
+		if rate, ok := st.rate(e); ok {
+			evidence = append(evidence, rate)
+		}
+
+		score := 0.0
+		for _, item := range evidence {
+			score += item.Score
+		}
+		if len(evidence) == 0 || score < st.config.Threshold {
+			return []hll.Event{}
+		}
+		return []hll.Event{hll.SuspiciousKillEvent{
+			GenericEvent: hll.GenericEvent{
+				EventType: hll.EVENT_SUSPICIOUS_KILL,
+				EventTime: e.EventTime,
+			},
+			Killer:   e.Killer,
+			Victim:   e.Victim,
+			Weapon:   e.Weapon,
+			Distance: distance,
+			Score:    score,
+			Evidence: evidence,
+		}}
+	case hll.MatchStartEvent:
+		st.reset()
+	case hll.MatchPhaseChangedEvent:
+		// the polls detect matches without a start in the logs, e.g. after a map change
+		newMatch := e.OldPhase == hll.MATCH_PHASE_UNKNOWN || e.OldPhase == hll.MATCH_PHASE_ENDED
+		if newMatch && e.NewPhase != hll.MATCH_PHASE_ENDED {
+			st.reset()
+		}
+	}
+	return []hll.Event{}
+}
+
+func (st *suspicionTracker) reset() {
+	st.recent = []suspicionKill{}
+	st.distances = make(map[hll.WeaponType]KillDistanceStats)
+}
+
+// compares the kills of the killer within the window to the average of all players with kills
+func (st *suspicionTracker) rate(kill hll.KillEvent) (hll.SuspicionEvidence, bool) {
+	if st.config.RateWindow <= 0 || st.config.RateFactor <= 0 {
+		return hll.SuspicionEvidence{}, false
+	}
+	st.recent = append(st.recent, suspicionKill{time: kill.EventTime, killer: kill.Killer.ID})
+	st.recent = slices.DeleteFunc(st.recent, func(recent suspicionKill) bool {
+		return kill.EventTime.Sub(recent.time) > st.config.RateWindow
+	})
+
+	perKiller := make(map[string]int)
+	for _, recent := range st.recent {
+		perKiller[recent.killer]++
+	}
+	kills := perKiller[kill.Killer.ID]
+	if len(perKiller) < suspicionMinKillers || kills < st.config.MinRateKills {
+		return hll.SuspicionEvidence{}, false
+	}
+
+	// the baseline leaves out the killer to not be skewed by the suspect
+	baseline := float64(len(st.recent)-kills) / float64(len(perKiller)-1)
+	factor := float64(kills) / baseline
+	if factor < st.config.RateFactor {
+		return hll.SuspicionEvidence{}, false
+	}
+	minutes := st.config.RateWindow.Minutes()
+	return hll.SuspicionEvidence{
+		Kind:   hll.SUSPICION_KILL_RATE,
+		Score:  factor / st.config.RateFactor,
+		Detail: fmt.Sprintf("%.1f kills per minute, server average %.1f", float64(kills)/minutes, baseline/minutes),
+	}, true
+}
+
+func (r *Rcon) KillDistances() (map[hll.WeaponType]KillDistanceStats, error) {
+	if !r.Events.enabled {
+		return nil, errEventsDisabled
+	}
+	return r.Events.suspicion.current(), nil
+}
@@ -0,0 +1,122 @@
+package rcon
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestSuspicionTracker(t *testing.T) {
+	now := time.Unix(1639148969, 0)
+	us := hll.DetailedPlayerInfo{PlayerInfo: hll.PlayerInfo{Name: "us", ID: "us"}, Faction: hll.FACTION_US, Position: hll.Position{X: 1000, Y: 1000, Z: 100}}
+	ger := hll.DetailedPlayerInfo{PlayerInfo: hll.PlayerInfo{Name: "ger", ID: "ger"}, Faction: hll.FACTION_GER, Position: hll.Position{X: 1000, Y: 4000, Z: 100}}
+	kill := func(killer hll.DetailedPlayerInfo, victim hll.DetailedPlayerInfo, weapon hll.WeaponIdentifier, at time.Time) hll.KillEvent {
+		return hll.KillEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_KILL, EventTime: at},
+			Killer:       killer.PlayerInfo,
+			Victim:       victim.PlayerInfo,
+			Weapon:       weapon.Weapon(),
+			Context:      hll.NewKillContext(killer, victim),
+		}
+	}
+	suspicious := func(t *testing.T, events []hll.Event) hll.SuspiciousKillEvent {
+		t.Helper()
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+		event, ok := events[0].(hll.SuspiciousKillEvent)
+		if !ok {
+			t.Fatalf("Expected SuspiciousKillEvent, but got %T", events[0])
+		}
+		return event
+	}
+
+	t.Run("Ordinary kills should not be suspicious", func(t *testing.T) {
+		st := newSuspicionTracker(DefaultSuspicionConfig())
+		if events := st.process(kill(us, ger, hll.WEAPON_M1_GARAND, now)); len(events) != 0 {
+			t.Errorf("Expected no events, but got %v", events)
+		}
+		if events := st.process(kill(ger, us, hll.WEAPON_MP40, now)); len(events) != 0 {
+			t.Errorf("Expected no events, but got %v", events)
+		}
+		stats := st.current()[hll.WEAPON_TYPE_SUBMACHINE_GUN]
+		if stats.Kills != 1 || stats.Average() != 30 {
+			t.Errorf("Expected 1 submachine gun kill at 30m, but got %d at %.1fm", stats.Kills, stats.Average())
+		}
+	})
+
+	t.Run("Long range and foreign weapon kills should be suspicious", func(t *testing.T) {
+		st := newSuspicionTracker(DefaultSuspicionConfig())
+		far := us
+		far.Position.Y = ger.Position.Y + 20000
+
+		event := suspicious(t, st.process(kill(ger, far, hll.WEAPON_MP40, now)))
+		if event.Distance != 200 || event.Score != 2 || event.Evidence[0].Kind != hll.SUSPICION_LONG_RANGE {
+			t.Errorf("Expected a long range kill at 200m with score 2, but got %v", event)
+		}
+
+		// a picked up weapon alone is no reason for suspicion
+		if events := st.process(kill(us, ger, hll.WEAPON_MP40, now)); len(events) != 0 {
+			t.Errorf("Expected no events, but got %v", events)
+		}
+		event = suspicious(t, st.process(kill(far, ger, hll.WEAPON_MP40, now)))
+		if len(event.Evidence) != 2 || event.Evidence[1].Kind != hll.SUSPICION_FOREIGN_WEAPON || event.Score != 2.5 {
+			t.Errorf("Expected a long range kill with a foreign weapon with score 2.5, but got %v", event.Evidence)
+		}
+	})
+
+	t.Run("Last known positions should not be evidence for long range kills", func(t *testing.T) {
+		st := newSuspicionTracker(DefaultSuspicionConfig())
+		far := us
+		far.Position.Y = ger.Position.Y + 20000
+
+		event := kill(ger, far, hll.WEAPON_MP40, now)
+		event.Context.VictimPositionLive = false
+		if events := st.process(event); len(events) != 0 {
+			t.Errorf("Expected no events, but got %v", events)
+		}
+	})
+
+	t.Run("A kill rate far above the baseline should be suspicious", func(t *testing.T) {
+		st := newSuspicionTracker(DefaultSuspicionConfig())
+		for i := range 3 {
+			other := ger
+			other.ID = fmt.Sprintf("other-%d", i)
+			st.process(kill(other, us, hll.WEAPON_MP40, now))
+		}
+		var events []hll.Event
+		for i := range 10 {
+			events = st.process(kill(us, ger, hll.WEAPON_M1_GARAND, now.Add(time.Duration(i)*time.Second)))
+			if i < 9 && len(events) != 0 {
+				t.Fatalf("Expected no events before 10 kills, but got %v", events)
+			}
+		}
+		event := suspicious(t, events)
+		if event.Evidence[0].Kind != hll.SUSPICION_KILL_RATE || event.Score != 2.5 {
+			t.Errorf("Expected a kill rate 10 times the baseline with score 2.5, but got %v", event.Evidence)
+		}
+	})
+	t.Run("A new match detected by the polls should reset the distances", func(t *testing.T) {
+		st := newSuspicionTracker(DefaultSuspicionConfig())
+		st.process(kill(ger, us, hll.WEAPON_MP40, now))
+		phase := func(oldPhase, newPhase hll.MatchPhase) hll.MatchPhaseChangedEvent {
+			return hll.MatchPhaseChangedEvent{
+				GenericEvent: hll.GenericEvent{EventType: hll.EVENT_MATCH_PHASE_CHANGED, EventTime: now},
+				OldPhase:     oldPhase,
+				NewPhase:     newPhase,
+			}
+		}
+
+		st.process(phase(hll.MATCH_PHASE_WARMUP, hll.MATCH_PHASE_IN_PROGRESS))
+		st.process(phase(hll.MATCH_PHASE_IN_PROGRESS, hll.MATCH_PHASE_ENDED))
+		if kills := st.current()[hll.WEAPON_TYPE_SUBMACHINE_GUN].Kills; kills != 1 {
+			t.Errorf("Expected the kill to count until the next match, but got %d kills", kills)
+		}
+		st.process(phase(hll.MATCH_PHASE_ENDED, hll.MATCH_PHASE_WARMUP))
+		if distances := st.current(); len(distances) != 0 {
+			t.Errorf("Expected no distances, but got %v", distances)
+		}
+	})
+}
@@ -0,0 +1,350 @@
+package rcon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+	"github.com/zMoooooritz/go-let-loose/pkg/logger"
+)
+
+const (
+	outboxExtension   = ".payload"
+	outboxTempSuffix  = ".tmp" // payloads are written under this suffix first and renamed once complete
+	outboxFailedDir   = "failed"
+	defaultAttempts   = 5
+	defaultBackoff    = time.Second
+	defaultMaxBackoff = time.Minute
+	defaultTimeout    = 10 * time.Second
+)
+
+type WebhookConfig struct {
+	URL             string
+	Method          string            // POST if empty
+	Headers         map[string]string // e.g. an authorization header
+	ContentType     string            // application/json if empty
+	Template        string            // text/template executed with the event, the JSON of the event is sent if empty
+	Templates       map[hll.EventType]string
+	OutboxDirectory string // payloads are stored here until they were delivered
+	MaxAttempts     int    // attempts per payload before it is moved to the failed directory of the outbox
+	InitialBackoff  time.Duration
+	MaxBackoff      time.Duration
+	Timeout         time.Duration
+}
+
+// posts events to an HTTP endpoint, the payloads are persisted in an outbox first,
+// so they survive restarts and outages of the endpoint
+type WebhookSink struct {
+	config    WebhookConfig
+	template  *template.Template
+	templates map[hll.EventType]*template.Template
+	client    *http.Client
+	counter   atomic.Uint64
+
+	wakeup    chan struct{}
+	context   context.Context
+	cancel    context.CancelFunc
+	waitGroup *sync.WaitGroup
+}
+
+type permanentError struct {
+	status int
+}
+
+func (pe permanentError) Error() string {
+	return fmt.Sprintf("webhook rejected the payload with status %d", pe.status)
+}
+
+// the endpoint asked to wait before the next attempt
+type retryAfterError struct {
+	status int
+	delay  time.Duration
+}
+
+func (rae retryAfterError) Error() string {
+	return fmt.Sprintf("webhook responded with status %d, retry after %v", rae.status, rae.delay)
+}
+
+// the header holds either the seconds to wait or the date of the next attempt
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+var templateFuncs = template.FuncMap{
+	// renders a value as JSON, e.g. to embed a chat message into a JSON payload
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+func NewWebhookSink(cfg WebhookConfig) (*WebhookSink, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("the webhook requires a URL")
+	}
+	if cfg.OutboxDirectory == "" {
+		return nil, errors.New("the webhook requires an outbox directory")
+	}
+	if cfg.Method == "" {
+		cfg.Method = http.MethodPost
+	}
+	if cfg.ContentType == "" {
+		cfg.ContentType = "application/json"
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultAttempts
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	if err := os.MkdirAll(filepath.Join(cfg.OutboxDirectory, outboxFailedDir), 0o755); err != nil {
+		return nil, err
+	}
+	removeStalePayloads(cfg.OutboxDirectory)
+
+	ws := &WebhookSink{
+		config:    cfg,
+		templates: make(map[hll.EventType]*template.Template),
+		client:    &http.Client{Timeout: cfg.Timeout},
+		wakeup:    make(chan struct{}, 1),
+		waitGroup: &sync.WaitGroup{},
+	}
+
+	if cfg.Template != "" {
+		tmpl, err := template.New("webhook").Funcs(templateFuncs).Parse(cfg.Template)
+		if err != nil {
+			return nil, err
+		}
+		ws.template = tmpl
+	}
+	for eventType, text := range cfg.Templates {
+		tmpl, err := template.New(string(eventType)).Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		ws.templates[eventType] = tmpl
+	}
+
+	ws.context, ws.cancel = context.WithCancel(context.Background())
+	ws.waitGroup.Add(1)
+	go ws.run()
+	// deliver what is left over from a previous run
+	ws.notifyWorker()
+
+	return ws, nil
+}
+
+func (ws *WebhookSink) Write(event hll.Event) error {
+	payload, err := ws.payload(event)
+	if err != nil {
+		return err
+	}
+
+	// the name keeps the payloads in order, even across restarts
+	name := fmt.Sprintf("%s-%06d%s", time.Now().UTC().Format(journalTimeFormat), ws.counter.Add(1), outboxExtension)
+	path := filepath.Join(ws.config.OutboxDirectory, name)
+	if err := os.WriteFile(path+outboxTempSuffix, payload, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(path+outboxTempSuffix, path); err != nil {
+		return err
+	}
+
+	ws.notifyWorker()
+	return nil
+}
+
+// stops the delivery, payloads that were not delivered yet stay in the outbox
+func (ws *WebhookSink) Close() error {
+	ws.cancel()
+	ws.waitGroup.Wait()
+	return nil
+}
+
+func (ws *WebhookSink) payload(event hll.Event) ([]byte, error) {
+	tmpl := ws.template
+	if eventTemplate, ok := ws.templates[event.Type()]; ok {
+		tmpl = eventTemplate
+	}
+	if tmpl == nil {
+		return hll.MarshalEvent(event)
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, event); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// a payload that was not renamed into the outbox may have been written only partially before a crash
+func removeStalePayloads(directory string) {
+	stale, err := filepath.Glob(filepath.Join(directory, "*"+outboxExtension+outboxTempSuffix))
+	if err != nil {
+		logger.Error("reading webhook outbox failed", err)
+		return
+	}
+	for _, path := range stale {
+		logger.Warn("removing incomplete webhook payload", filepath.Base(path))
+		if err := os.Remove(path); err != nil {
+			logger.Error("removing incomplete webhook payload failed", err)
+		}
+	}
+}
+
+func (ws *WebhookSink) notifyWorker() {
+	select {
+	case ws.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (ws *WebhookSink) run() {
+	defer ws.waitGroup.Done()
+
+	for {
+		select {
+		case <-ws.context.Done():
+			return
+		case <-ws.wakeup:
+			ws.deliverOutbox()
+		}
+	}
+}
+
+func (ws *WebhookSink) deliverOutbox() {
+	entries, err := os.ReadDir(ws.config.OutboxDirectory)
+	if err != nil {
+		logger.Error("reading webhook outbox failed", err)
+		return
+	}
+
+	files := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), outboxExtension) {
+			files = append(files, entry.Name())
+		}
+	}
+	slices.Sort(files)
+
+	for _, name := range files {
+		if ws.context.Err() != nil {
+			return
+		}
+		ws.deliverFile(name)
+	}
+}
+
+func (ws *WebhookSink) deliverFile(name string) {
+	path := filepath.Join(ws.config.OutboxDirectory, name)
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		logger.Error("reading webhook payload failed", err)
+		ws.moveToFailed(name)
+		return
+	}
+
+	backoff := ws.config.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := ws.post(payload)
+		if err == nil {
+			if err := os.Remove(path); err != nil {
+				logger.Error("removing delivered webhook payload failed", err)
+			}
+			return
+		}
+		if ws.context.Err() != nil { // the payload is delivered after the next start
+			return
+		}
+
+		var permanent permanentError
+		if errors.As(err, &permanent) || attempt >= ws.config.MaxAttempts {
+			logger.Error("delivering webhook payload failed", name, err)
+			ws.moveToFailed(name)
+			return
+		}
+
+		delay := backoff
+		var retryAfter retryAfterError
+		if errors.As(err, &retryAfter) {
+			delay = retryAfter.delay
+		}
+		select {
+		case <-ws.context.Done():
+			return
+		case <-time.After(delay):
+		}
+		backoff = min(2*backoff, ws.config.MaxBackoff)
+	}
+}
+
+func (ws *WebhookSink) post(payload []byte) error {
+	request, err := http.NewRequestWithContext(ws.context, ws.config.Method, ws.config.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", ws.config.ContentType)
+	for key, value := range ws.config.Headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := ws.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return nil
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusServiceUnavailable:
+		if delay, ok := parseRetryAfter(response.Header.Get("Retry-After"), time.Now()); ok {
+			return retryAfterError{status: response.StatusCode, delay: delay}
+		}
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	case response.StatusCode == http.StatusRequestTimeout || response.StatusCode >= 500:
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	default:
+		return permanentError{status: response.StatusCode}
+	}
+}
+
+func (ws *WebhookSink) moveToFailed(name string) {
+	from := filepath.Join(ws.config.OutboxDirectory, name)
+	to := filepath.Join(ws.config.OutboxDirectory, outboxFailedDir, name)
+	if err := os.Rename(from, to); err != nil {
+		logger.Error("moving failed webhook payload failed", err)
+		os.Remove(from)
+	}
+}
@@ -0,0 +1,268 @@
+package rcon
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+type webhookServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	failures int
+	status   int
+	header   http.Header // sent along with the failures
+	bodies   []string
+}
+
+func newWebhookServer(failures int, status int) *webhookServer {
+	ws := &webhookServer{failures: failures, status: status}
+	ws.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws.mutex.Lock()
+		defer ws.mutex.Unlock()
+		if ws.failures > 0 {
+			ws.failures--
+			for key, values := range ws.header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(ws.status)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		ws.bodies = append(ws.bodies, string(body))
+	}))
+	return ws
+}
+
+func (ws *webhookServer) received() []string {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+	return append([]string{}, ws.bodies...)
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the condition to be met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func outboxFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+outboxExtension))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func chatEvent(message string) hll.ChatEvent {
+	return hll.ChatEvent{
+		GenericEvent: hll.GenericEvent{EventType: hll.EVENT_CHAT, EventTime: time.Unix(1700000000, 0).UTC()},
+		Player:       hll.PlayerInfo{Name: "Alice", ID: "1"},
+		Message:      message,
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Run("retries with backoff", func(t *testing.T) {
+		server := newWebhookServer(2, http.StatusInternalServerError)
+		defer server.Close()
+
+		dir := t.TempDir()
+		sink, err := NewWebhookSink(WebhookConfig{URL: server.URL, OutboxDirectory: dir, InitialBackoff: time.Millisecond})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sink.Close()
+
+		if err := sink.Write(chatEvent("hello")); err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		waitFor(t, func() bool { return len(server.received()) == 1 })
+
+		event, err := hll.UnmarshalEvent([]byte(server.received()[0]))
+		if err != nil {
+			t.Fatalf("Expected the event as JSON, but got %v", err)
+		}
+		if chat, ok := event.(hll.ChatEvent); !ok || chat.Message != "hello" {
+			t.Errorf("Expected the chat message, but got %+v", event)
+		}
+		waitFor(t, func() bool { return len(outboxFiles(t, dir)) == 0 })
+	})
+
+	t.Run("request timeouts are retried", func(t *testing.T) {
+		server := newWebhookServer(1, http.StatusRequestTimeout)
+		defer server.Close()
+
+		sink, err := NewWebhookSink(WebhookConfig{URL: server.URL, OutboxDirectory: t.TempDir(), InitialBackoff: time.Millisecond})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sink.Close()
+
+		if err := sink.Write(chatEvent("hello")); err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		waitFor(t, func() bool { return len(server.received()) == 1 })
+	})
+
+	t.Run("rate limits are honored", func(t *testing.T) {
+		server := newWebhookServer(1, http.StatusTooManyRequests)
+		server.header = http.Header{"Retry-After": []string{"1"}}
+		defer server.Close()
+
+		sink, err := NewWebhookSink(WebhookConfig{URL: server.URL, OutboxDirectory: t.TempDir(), InitialBackoff: time.Millisecond})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sink.Close()
+
+		started := time.Now()
+		if err := sink.Write(chatEvent("hello")); err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		waitFor(t, func() bool { return len(server.received()) == 1 })
+		if elapsed := time.Since(started); elapsed < time.Second {
+			t.Errorf("Expected the retry to wait for a second, but it took %v", elapsed)
+		}
+	})
+
+	t.Run("template payload", func(t *testing.T) {
+		server := newWebhookServer(0, http.StatusOK)
+		defer server.Close()
+
+		sink, err := NewWebhookSink(WebhookConfig{
+			URL:             server.URL,
+			OutboxDirectory: t.TempDir(),
+			Templates: map[hll.EventType]string{
+				hll.EVENT_CHAT: `{"content": {{json (printf "%s: %s" .Player.Name .Message)}}}`,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sink.Close()
+
+		if err := sink.Write(chatEvent(`say "hi"`)); err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		waitFor(t, func() bool { return len(server.received()) == 1 })
+
+		expected := `{"content": "Alice: say \"hi\""}`
+		if server.received()[0] != expected {
+			t.Errorf("Expected %s, but got %s", expected, server.received()[0])
+		}
+	})
+
+	t.Run("rejected payloads are kept aside", func(t *testing.T) {
+		server := newWebhookServer(1, http.StatusBadRequest)
+		defer server.Close()
+
+		dir := t.TempDir()
+		sink, err := NewWebhookSink(WebhookConfig{URL: server.URL, OutboxDirectory: dir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sink.Close()
+
+		sink.Write(chatEvent("rejected"))
+		waitFor(t, func() bool {
+			failed, _ := os.ReadDir(filepath.Join(dir, outboxFailedDir))
+			return len(failed) == 1
+		})
+		if len(server.received()) != 0 {
+			t.Errorf("Expected no delivered payloads, but got %d", len(server.received()))
+		}
+	})
+
+	t.Run("outbox survives restarts", func(t *testing.T) {
+		server := newWebhookServer(0, http.StatusOK)
+		url := server.URL
+		server.Close()
+
+		dir := t.TempDir()
+		sink, err := NewWebhookSink(WebhookConfig{URL: url, OutboxDirectory: dir, InitialBackoff: time.Hour})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sink.Write(chatEvent("offline"))
+		sink.Close()
+		if len(outboxFiles(t, dir)) != 1 {
+			t.Fatalf("Expected the payload to stay in the outbox")
+		}
+
+		server = newWebhookServer(0, http.StatusOK)
+		defer server.Close()
+		sink, err = NewWebhookSink(WebhookConfig{URL: server.URL, OutboxDirectory: dir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sink.Close()
+
+		waitFor(t, func() bool { return len(server.received()) == 1 })
+	})
+
+	t.Run("incomplete payloads are removed on start", func(t *testing.T) {
+		server := newWebhookServer(0, http.StatusOK)
+		defer server.Close()
+
+		dir := t.TempDir()
+		stale := filepath.Join(dir, "20231114-221320-000001"+outboxExtension+outboxTempSuffix)
+		if err := os.WriteFile(stale, []byte(`{"version":1,"ty`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		sink, err := NewWebhookSink(WebhookConfig{URL: server.URL, OutboxDirectory: dir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sink.Close()
+
+		if _, err := os.Stat(stale); !os.IsNotExist(err) {
+			t.Errorf("Expected the incomplete payload to be removed, but got %v", err)
+		}
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 20, 15, 30, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"120":                           2 * time.Minute,
+		"Sun, 01 Jun 2025 20:16:00 GMT": 30 * time.Second,
+		"Sun, 01 Jun 2025 20:00:00 GMT": 0,
+	}
+	for header, expected := range tests {
+		if delay, ok := parseRetryAfter(header, now); !ok || delay != expected {
+			t.Errorf("Expected %v for %q, but got %v", expected, header, delay)
+		}
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("Expected an invalid header to be ignored")
+	}
+}
+
+func TestSinkObserver(t *testing.T) {
+	var buffer bytes.Buffer
+	observer := &sinkObserver{sink: NewJSONSink(&buffer), filter: EventTypeFilter(hll.EVENT_CHAT)}
+
+	observer.Notify(chatEvent("gg"))
+	observer.Notify(hll.GenericEvent{EventType: hll.EVENT_GENERIC})
+
+	lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 line, but got %d", len(lines))
+	}
+	if _, err := hll.UnmarshalEvent(lines[0]); err != nil {
+		t.Errorf("Expected a valid event, but got %v", err)
+	}
+}
@@ -2,7 +2,9 @@ package rcon
 
 import (
 	"context"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/zMoooooritz/go-let-loose/pkg/hll"
 )
@@ -16,11 +18,65 @@ type rconEvents struct {
 	eventSystem
 }
 
+const (
+	defaultLogsInterval       = 400 * time.Millisecond
+	defaultServerInfoInterval = time.Second
+	reorderInterval           = 100 * time.Millisecond
+)
+
+type FetcherConfig struct {
+	Disabled bool
+	Interval time.Duration // pause between two fetches, the default interval is used if not positive
+}
+
+type EventsConfig struct {
+	Logs                 FetcherConfig   // log based events like kills, chat and connections
+	ServerInfo           FetcherConfig   // events derived from polling the session and the player list
+	MinMovement          int             // minimal distance in cm a player has to move for a PlayerPositionChangedEvent
+	DisabledEvents       []hll.EventType // these events are never passed to any observer and not derived if avoidable
+	ReorderWindow        time.Duration   // if positive, events are held back this long and delivered ordered by their time
+	SnapshotInterval     time.Duration   // if positive, a ServerSnapshotEvent is emitted in this interval, the density heatmaps are sampled from it
+	Streaks              StreakConfig
+	PopulationThresholds []int // a PopulationThresholdCrossedEvent is emitted once the player count crosses one of these values, falling needs a small margin
+	Proximity            []ProximityRule
+	Suspicion            SuspicionConfig
+}
+
+func DefaultEventsConfig() EventsConfig {
+	return EventsConfig{
+		Logs:                 FetcherConfig{Interval: defaultLogsInterval},
+		ServerInfo:           FetcherConfig{Interval: defaultServerInfoInterval},
+		SnapshotInterval:     30 * time.Second,
+		Streaks:              DefaultStreakConfig(),
+		PopulationThresholds: []int{20, 40, 70},
+		Proximity:            DefaultProximityRules(),
+		Suspicion:            DefaultSuspicionConfig(),
+	}
+}
+
+func (cfg EventsConfig) isDisabled(eventType hll.EventType) bool {
+	return slices.Contains(cfg.DisabledEvents, eventType)
+}
+
+// whether one of the events is delivered, so the fetchers can skip deriving the others
+func (cfg EventsConfig) anyEnabled(eventTypes ...hll.EventType) bool {
+	for _, eventType := range eventTypes {
+		if !cfg.isDisabled(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
 func WithEvents() RconOption {
+	return WithEventsConfig(DefaultEventsConfig())
+}
+
+func WithEventsConfig(cfg EventsConfig) RconOption {
 	return func(r *Rcon) {
 		r.Events = &rconEvents{
 			enabled:     true,
-			eventSystem: *newEventSystem(r),
+			eventSystem: *newEventSystem(r, cfg),
 		}
 	}
 }
@@ -35,26 +91,70 @@ func WithoutEvents() RconOption {
 
 type eventSystem struct {
 	*eventNotifier
+	*eventTrackers
+	replay chan hll.Event
 
 	context   context.Context
 	cancel    context.CancelFunc
 	waitGroup *sync.WaitGroup
 }
 
-func newEventSystem(rcn *Rcon) *eventSystem {
+// the state derived from the fetched data, shared by the fetchers and the accessors of the Rcon
+type eventTrackers struct {
+	streaks      *streakTracker
+	population   *populationTracker
+	match        *matchTracker
+	sessions     *sessionTracker
+	strongpoints *strongpointTracker
+	proximity    *proximityTracker
+	crews        *crewTracker
+	suspicion    *suspicionTracker
+}
+
+func newEventTrackers(cfg EventsConfig) *eventTrackers {
+	return &eventTrackers{
+		streaks:      newStreakTracker(cfg.Streaks),
+		population:   newPopulationTracker(cfg.PopulationThresholds),
+		match:        newMatchTracker(),
+		sessions:     newSessionTracker(),
+		strongpoints: newStrongpointTracker(),
+		proximity:    newProximityTracker(cfg.Proximity),
+		crews:        newCrewTracker(),
+		suspicion:    newSuspicionTracker(cfg.Suspicion),
+	}
+}
+
+func newEventSystem(rcn *Rcon, cfg EventsConfig) *eventSystem {
 	eventChannel := make(chan hll.Event, channel_size)
+	replayChannel := make(chan hll.Event)
 
 	waitGroup := &sync.WaitGroup{}
 	context, cancel := context.WithCancel(context.Background())
 	eventNotifier := newEventNotifier()
+	trackers := newEventTrackers(cfg)
 
-	waitGroup.Add(3)
-	go eventHandlerRoutine(eventChannel, eventNotifier, context, waitGroup)
-	go logsFetcherRoutine(rcn, eventChannel, context, waitGroup)
-	go serverInfoFetcherRoutine(rcn, eventChannel, context, waitGroup)
+	if cfg.Logs.Interval <= 0 {
+		cfg.Logs.Interval = defaultLogsInterval
+	}
+	if cfg.ServerInfo.Interval <= 0 {
+		cfg.ServerInfo.Interval = defaultServerInfoInterval
+	}
+
+	waitGroup.Add(1)
+	go eventHandlerRoutine(eventChannel, replayChannel, eventNotifier, cfg, context, waitGroup)
+	if !cfg.Logs.Disabled {
+		waitGroup.Add(1)
+		go logsFetcherRoutine(rcn, eventChannel, cfg, trackers, context, waitGroup)
+	}
+	if !cfg.ServerInfo.Disabled {
+		waitGroup.Add(1)
+		go serverInfoFetcherRoutine(rcn, eventChannel, cfg, trackers, context, waitGroup)
+	}
 
 	return &eventSystem{
 		eventNotifier,
+		trackers,
+		replayChannel,
 		context,
 		cancel,
 		waitGroup,
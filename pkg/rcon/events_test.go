@@ -523,7 +523,7 @@ func TestPlayerInfoDiffToEvents(t *testing.T) {
 		}
 		expected := []hll.Event{}
 
-		result := playerInfoDiffToEvents(oldData, newData)
+		result := playerInfoDiffToEvents(oldData, newData, hll.Layer{})
 		if !reflect.DeepEqual(result, expected) {
 			t.Errorf("Expected %v, but got %v", expected, result)
 		}
@@ -539,7 +539,7 @@ func TestPlayerInfoDiffToEvents(t *testing.T) {
 			Team:       "Allies",
 		}
 
-		events := playerInfoDiffToEvents(oldData, newData)
+		events := playerInfoDiffToEvents(oldData, newData, hll.Layer{})
 		if len(events) != 1 {
 			t.Fatalf("Expected 1 event, but got %d", len(events))
 		}
@@ -570,7 +570,7 @@ func TestPlayerInfoDiffToEvents(t *testing.T) {
 			},
 		}
 
-		events := playerInfoDiffToEvents(oldData, newData)
+		events := playerInfoDiffToEvents(oldData, newData, hll.Layer{})
 		if len(events) != 1 {
 			t.Fatalf("Expected 1 event, but got %d", len(events))
 		}
@@ -595,7 +595,7 @@ func TestPlayerInfoDiffToEvents(t *testing.T) {
 			Role:       "Officer",
 		}
 
-		events := playerInfoDiffToEvents(oldData, newData)
+		events := playerInfoDiffToEvents(oldData, newData, hll.Layer{})
 		if len(events) != 1 {
 			t.Fatalf("Expected 1 event, but got %d", len(events))
 		}
@@ -620,7 +620,7 @@ func TestPlayerInfoDiffToEvents(t *testing.T) {
 			Loadout:    "Sniper",
 		}
 
-		events := playerInfoDiffToEvents(oldData, newData)
+		events := playerInfoDiffToEvents(oldData, newData, hll.Layer{})
 		if len(events) != 1 {
 			t.Fatalf("Expected 1 event, but got %d", len(events))
 		}
@@ -645,7 +645,7 @@ func TestPlayerInfoDiffToEvents(t *testing.T) {
 			Score:      hll.Score{Combat: 15, Offense: 5, Defense: 10, Support: 3},
 		}
 
-		events := playerInfoDiffToEvents(oldData, newData)
+		events := playerInfoDiffToEvents(oldData, newData, hll.Layer{})
 		if len(events) != 1 {
 			t.Fatalf("Expected 1 event, but got %d", len(events))
 		}
@@ -660,4 +660,468 @@ func TestPlayerInfoDiffToEvents(t *testing.T) {
 			t.Errorf("Expected score update, but got old: %v, new: %v", event.OldScore, event.NewScore)
 		}
 	})
+
+	t.Run("Player spawns", func(t *testing.T) {
+		oldData := hll.DetailedPlayerInfo{
+			PlayerInfo: hll.PlayerInfo{Name: "Player1", ID: "spawn-1"},
+		}
+		newData := hll.DetailedPlayerInfo{
+			PlayerInfo: hll.PlayerInfo{Name: "Player1", ID: "spawn-1"},
+			Position:   hll.Position{X: -65000, Y: -39000, Z: 1300},
+		}
+
+		events := playerInfoDiffToEvents(oldData, newData, hll.LAYER_CARENTAN_WARFARE.Layer())
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+
+		event, ok := events[0].(hll.PlayerSpawnedEvent)
+		if !ok {
+			t.Fatalf("Expected PlayerSpawnedEvent, but got %T", events[0])
+		}
+
+		if event.Position != newData.Position || event.GridReference != "B4-3" {
+			t.Errorf("Expected spawn at %v in B4-3, but got %v in %s", newData.Position, event.Position, event.GridReference)
+		}
+		if event.Strongpoint == nil || event.Strongpoint.ID != "BLACTOT" {
+			t.Errorf("Expected nearest strongpoint BLACTOT, but got %v", event.Strongpoint)
+		}
+	})
+
+	t.Run("Player despawns without death", func(t *testing.T) {
+		oldData := hll.DetailedPlayerInfo{
+			PlayerInfo: hll.PlayerInfo{Name: "Player1", ID: "despawn-1"},
+			Position:   hll.Position{X: 100, Y: 100, Z: 100},
+		}
+		newData := hll.DetailedPlayerInfo{
+			PlayerInfo: hll.PlayerInfo{Name: "Player1", ID: "despawn-1"},
+		}
+
+		events := playerInfoDiffToEvents(oldData, newData, hll.Layer{})
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+
+		event, ok := events[0].(hll.PlayerDespawnedEvent)
+		if !ok {
+			t.Fatalf("Expected PlayerDespawnedEvent, but got %T", events[0])
+		}
+
+		if event.Reason != hll.DESPAWN_REASON_REDEPLOY || event.Position != oldData.Position {
+			t.Errorf("Expected redeploy at %v, but got %s at %v", oldData.Position, event.Reason, event.Position)
+		}
+	})
+
+	t.Run("Player despawns after a logged death", func(t *testing.T) {
+		oldData := hll.DetailedPlayerInfo{
+			PlayerInfo: hll.PlayerInfo{Name: "Player1", ID: "despawn-2"},
+			Position:   hll.Position{X: 100, Y: 100, Z: 100},
+		}
+		newData := hll.DetailedPlayerInfo{
+			PlayerInfo: hll.PlayerInfo{Name: "Player1", ID: "despawn-2"},
+		}
+		killer := hll.PlayerInfo{Name: "Player2", ID: "despawn-killer"}
+		recordDeath(hll.DeathEvent{Victim: newData.PlayerInfo, Killer: killer})
+
+		events := playerInfoDiffToEvents(oldData, newData, hll.Layer{})
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+
+		event, ok := events[0].(hll.PlayerDespawnedEvent)
+		if !ok {
+			t.Fatalf("Expected PlayerDespawnedEvent, but got %T", events[0])
+		}
+
+		if event.Reason != hll.DESPAWN_REASON_DEATH || event.Killer != killer {
+			t.Errorf("Expected death by %v, but got %s by %v", killer, event.Reason, event.Killer)
+		}
+	})
+
+	t.Run("Player levels up", func(t *testing.T) {
+		oldData := hll.DetailedPlayerInfo{
+			PlayerInfo: hll.PlayerInfo{Name: "Player1"},
+			Level:      41,
+		}
+		newData := hll.DetailedPlayerInfo{
+			PlayerInfo: hll.PlayerInfo{Name: "Player1"},
+			Level:      42,
+		}
+
+		events := playerInfoDiffToEvents(oldData, newData, hll.Layer{})
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+
+		event, ok := events[0].(hll.PlayerLevelUpEvent)
+		if !ok {
+			t.Fatalf("Expected PlayerLevelUpEvent, but got %T", events[0])
+		}
+
+		if event.OldLevel != oldData.Level || event.NewLevel != newData.Level {
+			t.Errorf("Expected level up from %d to %d, but got from %d to %d", oldData.Level, newData.Level, event.OldLevel, event.NewLevel)
+		}
+	})
+
+	t.Run("Player destroys a vehicle", func(t *testing.T) {
+		oldData := hll.DetailedPlayerInfo{
+			PlayerInfo: hll.PlayerInfo{Name: "Player1"},
+			Role:       hll.ROLE_ANTITANK,
+		}
+		newData := hll.DetailedPlayerInfo{
+			PlayerInfo:        hll.PlayerInfo{Name: "Player1"},
+			Role:              hll.ROLE_ANTITANK,
+			VehiclesDestroyed: 1,
+		}
+
+		events := playerInfoDiffToEvents(oldData, newData, hll.Layer{})
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+
+		if _, ok := events[0].(hll.PlayerVehicleDestroyedEvent); !ok {
+			t.Fatalf("Expected PlayerVehicleDestroyedEvent, but got %T", events[0])
+		}
+	})
+}
+
+func TestEnrichKillEvents(t *testing.T) {
+	killer := hll.DetailedPlayerInfo{
+		PlayerInfo: hll.PlayerInfo{Name: "Killer", ID: "enrich-killer"},
+		Team:       hll.TEAM_AXIS,
+		Role:       hll.ROLE_RIFLEMAN,
+		Position:   hll.Position{X: 0, Y: 0, Z: 100},
+	}
+	victim := hll.DetailedPlayerInfo{
+		PlayerInfo: hll.PlayerInfo{Name: "Victim", ID: "enrich-victim"},
+		Team:       hll.TEAM_ALLIES,
+		Role:       hll.ROLE_MEDIC,
+		Position:   hll.Position{X: 3000, Y: 4000, Z: 100},
+	}
+
+	t.Run("Unknown players should not get a context", func(t *testing.T) {
+		events := enrichKillEvents([]hll.Event{hll.KillEvent{
+			Killer: hll.PlayerInfo{Name: "Unknown", ID: "enrich-unknown"},
+			Victim: victim.PlayerInfo,
+		}})
+		if events[0].(hll.KillEvent).Context != nil {
+			t.Errorf("Expected no context, but got %v", events[0].(hll.KillEvent).Context)
+		}
+	})
+
+	t.Run("Known players should get a context with distance", func(t *testing.T) {
+		setPlayerInfo(killer)
+		setPlayerInfo(victim)
+
+		events := enrichKillEvents(logToEvents("[1:00 min (1639143555)] KILL: Killer(Axis/enrich-killer) -> Victim(Allies/enrich-victim) with MP40"))
+		if len(events) != 2 {
+			t.Fatalf("Expected 2 events, but got %d", len(events))
+		}
+
+		killEvent, ok := events[0].(hll.KillEvent)
+		if !ok || killEvent.Context == nil {
+			t.Fatalf("Expected KillEvent with context, but got %v", events[0])
+		}
+		if killEvent.Context.Killer.Role != hll.ROLE_RIFLEMAN || killEvent.Context.Victim.Team != hll.TEAM_ALLIES {
+			t.Errorf("Unexpected context %v", killEvent.Context)
+		}
+		if killEvent.Context.Distance != 50 {
+			t.Errorf("Expected distance of 50m, but got %f", killEvent.Context.Distance)
+		}
+		if !killEvent.Context.KillerPositionLive || !killEvent.Context.VictimPositionLive {
+			t.Errorf("Expected live positions, but got %v", killEvent.Context)
+		}
+
+		deathEvent, ok := events[1].(hll.DeathEvent)
+		if !ok || deathEvent.Context == nil {
+			t.Fatalf("Expected DeathEvent with context, but got %v", events[1])
+		}
+	})
+
+	t.Run("Despawned victim should keep its last known position", func(t *testing.T) {
+		despawned := victim
+		despawned.Position = hll.Position{}
+		setPlayerInfo(despawned)
+
+		events := enrichKillEvents([]hll.Event{hll.KillEvent{Killer: killer.PlayerInfo, Victim: victim.PlayerInfo}})
+		context := events[0].(hll.KillEvent).Context
+		if context == nil || context.Victim.Position != victim.Position {
+			t.Fatalf("Expected victim position %v, but got %v", victim.Position, context)
+		}
+		if !context.KillerPositionLive || context.VictimPositionLive {
+			t.Errorf("Expected only the killer position to be live, but got %t and %t", context.KillerPositionLive, context.VictimPositionLive)
+		}
+	})
+}
+
+func TestObjectiveCaptureToSectorEvents(t *testing.T) {
+	capture := func(oldScore, newScore hll.TeamData) hll.ObjectiveCaptureEvent {
+		return hll.ObjectiveCaptureEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_OBJECTIVE_CAPPED, EventTime: time.Now()},
+			OldScore:     oldScore,
+			NewScore:     newScore,
+		}
+	}
+
+	t.Run("Allies capture with known layout", func(t *testing.T) {
+		events := objectiveCaptureToSectorEvents(
+			capture(hll.TeamData{Allies: 2, Axis: 2}, hll.TeamData{Allies: 3, Axis: 2}),
+			hll.LAYER_CARENTAN_WARFARE.Layer(),
+			[]string{"Blactot", "Ruins", "Town Center", "Customs", "Cemetery"},
+			nil,
+		)
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+
+		event, ok := events[0].(hll.SectorCapturedEvent)
+		if !ok {
+			t.Fatalf("Expected SectorCapturedEvent, but got %T", events[0])
+		}
+		if event.Team != hll.TEAM_ALLIES || event.SectorIndex != 2 {
+			t.Errorf("Expected Allies to capture sector 2, but got %s capturing sector %d", event.Team, event.SectorIndex)
+		}
+		if event.String() != "Allies captured Town Center (3-2)" {
+			t.Errorf("Unexpected description %q", event.String())
+		}
+	})
+
+	t.Run("Axis capture on a mirrored map", func(t *testing.T) {
+		layer := hll.LAYER_UTAHBEACH_WARFARE.Layer()
+		events := objectiveCaptureToSectorEvents(
+			capture(hll.TeamData{Allies: 3, Axis: 2}, hll.TeamData{Allies: 2, Axis: 3}),
+			layer, nil, nil,
+		)
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+
+		event := events[0].(hll.SectorCapturedEvent)
+		if event.Team != hll.TEAM_AXIS || event.SectorIndex != 2 || event.Strongpoint != nil {
+			t.Errorf("Expected Axis to capture sector 2 without strongpoint, but got %s capturing sector %d (%v)", event.Team, event.SectorIndex, event.Strongpoint)
+		}
+	})
+
+	t.Run("Strongpoint guessed from capturing players", func(t *testing.T) {
+		layer := hll.LAYER_CARENTAN_WARFARE.Layer()
+		sectors, _ := layer.OrderedSectors()
+		target := sectors[4].CaptureZones[1].Strongpoint
+		players := []hll.DetailedPlayerInfo{
+			{Team: hll.TEAM_AXIS, Position: target.Center},
+			{Team: hll.TEAM_AXIS, Position: target.Center},
+			{Team: hll.TEAM_ALLIES, Position: sectors[4].CaptureZones[0].Strongpoint.Center},
+		}
+
+		events := objectiveCaptureToSectorEvents(
+			capture(hll.TeamData{Allies: 5, Axis: 0}, hll.TeamData{Allies: 4, Axis: 1}),
+			layer, nil, players,
+		)
+		if len(events) != 1 {
+			t.Fatalf("Expected 1 event, but got %d", len(events))
+		}
+
+		event := events[0].(hll.SectorCapturedEvent)
+		if event.SectorIndex != 4 || event.Strongpoint == nil || event.Strongpoint.ID != target.ID {
+			t.Errorf("Expected capture of %s in sector 4, but got %v in sector %d", target.ID, event.Strongpoint, event.SectorIndex)
+		}
+	})
+}
+
+func TestSessionInfoDiffToEvents(t *testing.T) {
+	base := hll.SessionInfo{MaxPlayerCount: 100, PlayerCount: 99, MaxQueueCount: 6, MaxVIPQueueCount: 2}
+
+	t.Run("first poll", func(t *testing.T) {
+		events := sessionInfoDiffToEvents(hll.SessionInfo{}, base)
+		if len(events) != 0 {
+			t.Errorf("Expected no events, but got %d", len(events))
+		}
+	})
+
+	t.Run("server full and queue", func(t *testing.T) {
+		newData := base
+		newData.PlayerCount = 100
+		newData.QueueCount = 2
+
+		events := sessionInfoDiffToEvents(base, newData)
+		if len(events) != 2 {
+			t.Fatalf("Expected 2 events, but got %d", len(events))
+		}
+		if _, ok := events[0].(hll.ServerFullEvent); !ok {
+			t.Errorf("Expected ServerFullEvent, but got %T", events[0])
+		}
+		queueEvent, ok := events[1].(hll.QueueChangedEvent)
+		if !ok || queueEvent.OldCount != 0 || queueEvent.NewCount != 2 || queueEvent.MaxCount != 6 {
+			t.Errorf("Expected queue change 0 -> 2, but got %+v", events[1])
+		}
+	})
+
+	t.Run("server not full and vip queue", func(t *testing.T) {
+		oldData := base
+		oldData.PlayerCount = 100
+		oldData.VIPQueueCount = 1
+
+		events := sessionInfoDiffToEvents(oldData, base)
+		if len(events) != 2 {
+			t.Fatalf("Expected 2 events, but got %d", len(events))
+		}
+		if _, ok := events[0].(hll.ServerNotFullEvent); !ok {
+			t.Errorf("Expected ServerNotFullEvent, but got %T", events[0])
+		}
+		if _, ok := events[1].(hll.VIPQueueChangedEvent); !ok {
+			t.Errorf("Expected VIPQueueChangedEvent, but got %T", events[1])
+		}
+	})
+}
+
+func TestServerViewDiffToEvents(t *testing.T) {
+	player := func(id string, team hll.TeamIdentifier, squad string, role hll.RoleIdentifier) hll.DetailedPlayerInfo {
+		return hll.DetailedPlayerInfo{
+			PlayerInfo: hll.PlayerInfo{Name: id, ID: id},
+			Team:       team,
+			Unit:       hll.Unit{Name: squad, ID: 1},
+			Role:       role,
+		}
+	}
+	commander := hll.DetailedPlayerInfo{
+		PlayerInfo: hll.PlayerInfo{Name: "cmd", ID: "cmd"},
+		Team:       hll.TEAM_ALLIES,
+		Unit:       hll.Unit{Name: "Command", ID: hll.CommandUnitID},
+		Role:       hll.ROLE_ARMYCOMMANDER,
+	}
+
+	oldView := hll.PlayersToServerView([]hll.DetailedPlayerInfo{
+		player("a1", hll.TEAM_ALLIES, "Able", hll.ROLE_OFFICER),
+		player("a2", hll.TEAM_ALLIES, "Able", hll.ROLE_RIFLEMAN),
+		player("b1", hll.TEAM_ALLIES, "Baker", hll.ROLE_OFFICER),
+		player("b2", hll.TEAM_ALLIES, "Baker", hll.ROLE_RIFLEMAN),
+		player("x1", hll.TEAM_AXIS, "Able", hll.ROLE_OFFICER),
+	})
+	newView := hll.PlayersToServerView([]hll.DetailedPlayerInfo{
+		commander,
+		player("a1", hll.TEAM_ALLIES, "Able", hll.ROLE_RIFLEMAN),
+		player("a2", hll.TEAM_ALLIES, "Able", hll.ROLE_OFFICER),
+		player("b2", hll.TEAM_ALLIES, "Baker", hll.ROLE_RIFLEMAN),
+		player("c1", hll.TEAM_ALLIES, "Charlie", hll.ROLE_OFFICER),
+		// unassigned players are no squad
+		player("u1", hll.TEAM_ALLIES, "", hll.ROLE_RIFLEMAN),
+	})
+
+	diff := hll.DiffServerViews(oldView, newView)
+	if diff.IsEmpty() {
+		t.Fatal("Expected a non empty diff")
+	}
+	if !hll.DiffServerViews(newView, newView).IsEmpty() {
+		t.Error("Expected an empty diff for identical views")
+	}
+
+	events := serverViewDiffToEvents(diff)
+	expected := []hll.EventType{
+		hll.EVENT_COMMANDER_TOOK_ROLE,
+		hll.EVENT_SQUAD_CREATED,
+		hll.EVENT_SQUAD_DISBANDED,
+		hll.EVENT_SQUAD_LEADER_CHANGED,
+		hll.EVENT_SQUAD_LEADERLESS,
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d", len(expected), len(events))
+	}
+	for i, eventType := range expected {
+		if events[i].Type() != eventType {
+			t.Errorf("Expected event %d to be %s, but got %s", i, eventType, events[i].Type())
+		}
+	}
+
+	if created := events[1].(hll.SquadCreatedEvent); created.Squad.Name != "Charlie" {
+		t.Errorf("Expected squad Charlie to be created, but got %s", created.Squad.Name)
+	}
+	if disbanded := events[2].(hll.SquadDisbandedEvent); disbanded.Squad.Team != hll.TEAM_AXIS {
+		t.Errorf("Expected the axis squad to be disbanded, but got %s", disbanded.Squad.Team)
+	}
+	if changed := events[3].(hll.SquadLeaderChangedEvent); changed.OldLeader.ID != "a1" || changed.NewLeader.ID != "a2" {
+		t.Errorf("Expected leader change a1 -> a2, but got %s -> %s", changed.OldLeader.ID, changed.NewLeader.ID)
+	}
+	if leaderless := events[4].(hll.SquadLeaderlessEvent); leaderless.Squad.Name != "Baker" || leaderless.OldLeader.ID != "b1" {
+		t.Errorf("Expected Baker to lose leader b1, but got %s losing %s", leaderless.Squad.Name, leaderless.OldLeader.ID)
+	}
+}
+
+func TestMovementFilter(t *testing.T) {
+	moved := func(id string, oldPos hll.Position, newPos hll.Position) hll.Event {
+		return hll.PlayerPositionChangedEvent{
+			GenericEvent: hll.GenericEvent{EventType: hll.EVENT_POSITION_CHANGED, EventTime: time.Now()},
+			Player:       hll.PlayerInfo{Name: id, ID: id},
+			OldPos:       oldPos,
+			NewPos:       newPos,
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		mf := newMovementFilter(0)
+		events := mf.filter([]hll.Event{moved("1", hll.Position{X: 1}, hll.Position{X: 2})})
+		if len(events) != 1 {
+			t.Errorf("Expected 1 event, but got %d", len(events))
+		}
+	})
+
+	t.Run("accumulated movement", func(t *testing.T) {
+		mf := newMovementFilter(1000)
+		steps := []hll.Position{{X: 1}, {X: 600}, {X: 1200}, {X: 1800}}
+
+		reported := 0
+		for i := 1; i < len(steps); i++ {
+			events := mf.filter([]hll.Event{moved("1", steps[i-1], steps[i])})
+			reported += len(events)
+			if i == 3 {
+				if len(events) != 1 {
+					t.Fatalf("Expected movement to be reported, but got %d events", len(events))
+				}
+				event := events[0].(hll.PlayerPositionChangedEvent)
+				if event.OldPos != steps[1] {
+					t.Errorf("Expected old position %v, but got %v", steps[1], event.OldPos)
+				}
+			}
+		}
+		if reported != 2 {
+			t.Errorf("Expected 2 reported movements, but got %d", reported)
+		}
+	})
+}
+
+func TestEventsConfig(t *testing.T) {
+	if cfg := (EventsConfig{}); cfg.Logs.Disabled || cfg.ServerInfo.Disabled {
+		t.Error("Expected all fetchers to be enabled in a zero config")
+	}
+	cfg := DefaultEventsConfig()
+	if cfg.Logs.Disabled || cfg.ServerInfo.Disabled {
+		t.Error("Expected all fetchers to be enabled by default")
+	}
+	if cfg.isDisabled(hll.EVENT_POSITION_CHANGED) {
+		t.Error("Expected no events to be disabled by default")
+	}
+
+	cfg.DisabledEvents = []hll.EventType{hll.EVENT_POSITION_CHANGED}
+	if !cfg.isDisabled(hll.EVENT_POSITION_CHANGED) || cfg.isDisabled(hll.EVENT_KILL) {
+		t.Error("Expected only position changes to be disabled")
+	}
+	if cfg.anyEnabled(hll.EVENT_POSITION_CHANGED) || !cfg.anyEnabled(hll.EVENT_POSITION_CHANGED, hll.EVENT_KILL) {
+		t.Error("Expected only the kill to be enabled")
+	}
+}
+
+func TestSectorLayout(t *testing.T) {
+	layout := &sectorLayout{}
+	layout.set(hll.LAYER_CARENTAN_WARFARE, []string{"Blactot"})
+	if objectives := layout.get(hll.LAYER_CARENTAN_WARFARE); len(objectives) != 1 {
+		t.Fatalf("Expected 1 objective, but got %v", objectives)
+	}
+
+	layout.processMatch(hll.MatchPhaseChangedEvent{OldPhase: hll.MATCH_PHASE_WARMUP, NewPhase: hll.MATCH_PHASE_IN_PROGRESS})
+	if objectives := layout.get(hll.LAYER_CARENTAN_WARFARE); len(objectives) != 1 {
+		t.Errorf("Expected the layout to be kept during the match, but got %v", objectives)
+	}
+
+	layout.processMatch(hll.MatchPhaseChangedEvent{OldPhase: hll.MATCH_PHASE_IN_PROGRESS, NewPhase: hll.MATCH_PHASE_ENDED})
+	if objectives := layout.get(hll.LAYER_CARENTAN_WARFARE); len(objectives) != 0 {
+		t.Errorf("Expected the layout to be cleared after the match, but got %v", objectives)
+	}
 }
@@ -0,0 +1,316 @@
+package rcon
+
+import (
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+type HeatmapKind string
+
+const (
+	HEATMAP_KILLS   HeatmapKind = "Kills"   // the positions of the killers
+	HEATMAP_DEATHS  HeatmapKind = "Deaths"  // the positions of the victims
+	HEATMAP_DENSITY HeatmapKind = "Density" // the player positions sampled from the server snapshots
+)
+
+const defaultHeatmapResolution = 4
+
+// counts positions in bins laid over the grid of a layer
+type Heatmap struct {
+	Layer      hll.Layer
+	Kind       HeatmapKind
+	Resolution int   // bins per grid square along each axis
+	Columns    int   // bins along the x axis
+	Rows       int   // bins along the y axis
+	Bins       []int // row by row, the first row is the top of the map
+	Total      int
+}
+
+// a resolution of one bins by grid square, values below one fall back to four bins per square
+func NewHeatmap(layer hll.Layer, kind HeatmapKind, resolution int) Heatmap {
+	if resolution < 1 {
+		resolution = defaultHeatmapResolution
+	}
+	columns := (layer.Grid.Max.X - layer.Grid.Min.X + 1) * resolution
+	rows := (layer.Grid.Max.Y - layer.Grid.Min.Y + 1) * resolution
+	return Heatmap{
+		Layer:      layer,
+		Kind:       kind,
+		Resolution: resolution,
+		Columns:    max(columns, 0),
+		Rows:       max(rows, 0),
+		Bins:       make([]int, max(columns*rows, 0)),
+	}
+}
+
+// positions outside of the grid and unknown positions are ignored
+func (h *Heatmap) Add(pos hll.Position) bool {
+	if !pos.IsActive() {
+		return false
+	}
+	column, row, ok := h.bin(pos)
+	if !ok {
+		return false
+	}
+	h.Bins[row*h.Columns+column]++
+	h.Total++
+	return true
+}
+
+func (h Heatmap) Count(column, row int) int {
+	if column < 0 || column >= h.Columns || row < 0 || row >= h.Rows {
+		return 0
+	}
+	return h.Bins[row*h.Columns+column]
+}
+
+func (h Heatmap) Max() int {
+	if len(h.Bins) == 0 {
+		return 0
+	}
+	return slices.Max(h.Bins)
+}
+
+func (h Heatmap) Clone() Heatmap {
+	h.Bins = slices.Clone(h.Bins)
+	return h
+}
+
+// the world size of a bin in cm
+func (h Heatmap) binSize() float64 {
+	return h.Layer.Grid.Scale / float64(h.Resolution)
+}
+
+// the world position of the top left corner of the grid
+func (h Heatmap) origin() (float64, float64) {
+	return h.Layer.Grid.GridToWorldMin(h.Layer.Grid.Min)
+}
+
+func (h Heatmap) bin(pos hll.Position) (int, int, bool) {
+	if h.Layer.Grid.Scale <= 0 {
+		return 0, 0, false
+	}
+	minX, minY := h.origin()
+	column := int(math.Floor((pos.X - minX) / h.binSize()))
+	row := int(math.Floor((pos.Y - minY) / h.binSize()))
+	if column < 0 || column >= h.Columns || row < 0 || row >= h.Rows {
+		return 0, 0, false
+	}
+	return column, row, true
+}
+
+// the kill, death and density heatmaps of one layer within a period,
+// the density is sampled from the ServerSnapshotEvents only and stays empty without EventsConfig.SnapshotInterval
+type HeatmapSet struct {
+	Layer   hll.Layer
+	Start   time.Time
+	End     time.Time
+	Kills   Heatmap
+	Deaths  Heatmap
+	Density Heatmap
+}
+
+func newHeatmapSet(layer hll.Layer, start time.Time, resolution int) *HeatmapSet {
+	return &HeatmapSet{
+		Layer:   layer,
+		Start:   start,
+		Kills:   NewHeatmap(layer, HEATMAP_KILLS, resolution),
+		Deaths:  NewHeatmap(layer, HEATMAP_DEATHS, resolution),
+		Density: NewHeatmap(layer, HEATMAP_DENSITY, resolution),
+	}
+}
+
+func (hs HeatmapSet) clone() HeatmapSet {
+	hs.Kills = hs.Kills.Clone()
+	hs.Deaths = hs.Deaths.Clone()
+	hs.Density = hs.Density.Clone()
+	return hs
+}
+
+// adds the positions carried by the event
+func (hs *HeatmapSet) add(event hll.Event) {
+	switch e := event.(type) {
+	case hll.KillEvent:
+		if e.Context != nil {
+			hs.Kills.Add(e.Context.Killer.Position)
+			hs.Deaths.Add(e.Context.Victim.Position)
+		}
+	case hll.ServerSnapshotEvent:
+		for _, player := range e.Players {
+			hs.Density.Add(player.Position)
+		}
+	}
+	if event.Time().After(hs.End) {
+		hs.End = event.Time()
+	}
+}
+
+// the layer that is played according to the event
+func eventLayer(event hll.Event) (hll.Layer, bool) {
+	switch e := event.(type) {
+	case hll.MatchPhaseChangedEvent:
+		return e.Match.Layer, e.Match.Layer.ID != ""
+	case hll.ServerSnapshotEvent:
+		layer, err := hll.ParseLayer(e.SessionInfo.MapID)
+		return layer, err == nil
+	}
+	return hll.Layer{}, false
+}
+
+// records the heatmaps of the running match
+type HeatmapRecorder struct {
+	resolution int
+	layer      hll.Layer
+	current    *HeatmapSet
+	ended      bool
+	callbacks  []func(HeatmapSet)
+	mutex      sync.Mutex
+}
+
+// subscribes the recorder to the events of the rcon, a resolution below one falls back to four bins per grid square;
+// the density heatmap requires a positive EventsConfig.SnapshotInterval
+func NewHeatmapRecorder(rcn *Rcon, resolution int) (*HeatmapRecorder, error) {
+	if !rcn.Events.enabled {
+		return nil, errEventsDisabled
+	}
+	recorder := newHeatmapRecorder(resolution)
+	if sessionInfo, err := rcn.GetSessionInfo(); err == nil {
+		if layer, err := hll.ParseLayer(sessionInfo.MapID); err == nil {
+			recorder.layer = layer
+			recorder.current = newHeatmapSet(layer, time.Now(), resolution)
+		}
+	}
+	rcn.Events.Register(recorder)
+	return recorder, nil
+}
+
+func newHeatmapRecorder(resolution int) *HeatmapRecorder {
+	return &HeatmapRecorder{resolution: resolution}
+}
+
+// the callback receives the heatmaps of every finished match
+func (hr *HeatmapRecorder) OnMatchEnd(callback func(HeatmapSet)) {
+	hr.mutex.Lock()
+	defer hr.mutex.Unlock()
+	hr.callbacks = append(hr.callbacks, callback)
+}
+
+// the heatmaps of the running or the last finished match, false as long as the layer is unknown
+func (hr *HeatmapRecorder) Current() (HeatmapSet, bool) {
+	hr.mutex.Lock()
+	defer hr.mutex.Unlock()
+	if hr.current == nil {
+		return HeatmapSet{}, false
+	}
+	return hr.current.clone(), true
+}
+
+func (hr *HeatmapRecorder) Notify(event hll.Event) {
+	hr.mutex.Lock()
+
+	if layer, ok := eventLayer(event); ok && layer.ID != hr.layer.ID {
+		hr.layer = layer
+		hr.start(event.Time())
+	}
+
+	var finished *HeatmapSet
+	switch e := event.(type) {
+	case hll.MatchStartEvent:
+		hr.start(e.EventTime)
+	case hll.MatchEndEvent:
+		finished = hr.finish(e.EventTime)
+	case hll.MatchPhaseChangedEvent:
+		switch {
+		case e.NewPhase == hll.MATCH_PHASE_ENDED:
+			finished = hr.finish(e.EventTime)
+		case hr.ended:
+			// the next match on the same layer
+			hr.start(e.EventTime)
+		}
+	default:
+		if hr.current != nil && !hr.ended {
+			hr.current.add(event)
+		}
+	}
+	callbacks := slices.Clone(hr.callbacks)
+	hr.mutex.Unlock()
+
+	if finished != nil {
+		for _, callback := range callbacks {
+			callback(*finished)
+		}
+	}
+}
+
+func (hr *HeatmapRecorder) start(at time.Time) {
+	hr.ended = false
+	if hr.layer.ID == "" {
+		hr.current = nil
+		return
+	}
+	hr.current = newHeatmapSet(hr.layer, at, hr.resolution)
+}
+
+// the end is reported by the log and the poll, only the first one finishes the match
+func (hr *HeatmapRecorder) finish(at time.Time) *HeatmapSet {
+	if hr.current == nil || hr.ended {
+		return nil
+	}
+	hr.ended = true
+	hr.current.End = at
+	finished := hr.current.clone()
+	return &finished
+}
+
+// aggregates the heatmaps of all matches on the layer within the period from the journal in the directory,
+// a zero time leaves the period open on that side; the density heatmap requires the snapshots to be journaled
+func JournalHeatmaps(directory string, layer hll.LayerIdentifier, from, to time.Time, resolution int) (HeatmapSet, error) {
+	played, err := hll.ParseLayer(string(layer))
+	if err != nil {
+		return HeatmapSet{}, err
+	}
+	reader, err := OpenJournal(directory)
+	if err != nil {
+		return HeatmapSet{}, err
+	}
+	defer reader.Close()
+	return heatmapsOf(reader, played, from, to, resolution)
+}
+
+func heatmapsOf(reader eventReader, layer hll.Layer, from, to time.Time, resolution int) (HeatmapSet, error) {
+	set := newHeatmapSet(layer, from, resolution)
+	var current hll.LayerIdentifier
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return HeatmapSet{}, err
+		}
+		if played, ok := eventLayer(event); ok {
+			current = played.ID
+		}
+		if event.Time().Before(from) || current != layer.ID {
+			continue
+		}
+		// the journal is written in the order of arrival, so an earlier event may still follow
+		if !to.IsZero() && event.Time().After(to) {
+			continue
+		}
+		if set.Start.IsZero() {
+			set.Start = event.Time()
+		}
+		set.add(event)
+	}
+	if !to.IsZero() {
+		set.End = to
+	}
+	return *set, nil
+}
@@ -0,0 +1,167 @@
+package rcon
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+// the rendered size of one grid square in pixels
+const heatmapSquareSize = 64
+
+var (
+	heatmapBackground  = color.RGBA{R: 0x20, G: 0x22, B: 0x25, A: 0xff}
+	heatmapGridLine    = color.RGBA{R: 0x70, G: 0x70, B: 0x70, A: 0xff}
+	heatmapStrongpoint = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	heatmapSector      = color.RGBA{R: 0xc0, G: 0xc0, B: 0xc0, A: 0xff}
+)
+
+var errHeatmapResolution = errors.New("heatmap resolution below 1")
+
+// the color stops from few to many positions in a bin
+var heatmapRamp = []color.RGBA{
+	{R: 0x1e, G: 0x3a, B: 0xff, A: 0xff},
+	{R: 0x00, G: 0xd0, B: 0xd0, A: 0xff},
+	{R: 0x40, G: 0xe0, B: 0x40, A: 0xff},
+	{R: 0xff, G: 0xe0, B: 0x00, A: 0xff},
+	{R: 0xff, G: 0x20, B: 0x10, A: 0xff},
+}
+
+// renders the heatmap with the grid, the sectors and the strongpoints of the layer as SVG
+func (h Heatmap) SVG(w io.Writer) error {
+	if h.Layer.Grid.Scale <= 0 {
+		return hll.ErrNoGrid
+	}
+	if h.Resolution < 1 {
+		return errHeatmapResolution
+	}
+	writer := bufio.NewWriter(w)
+	width, height := h.imageSize()
+	maxCount := h.Max()
+	binSize := float64(heatmapSquareSize) / float64(h.Resolution)
+
+	fmt.Fprintf(writer, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n", width, height, width, height)
+	fmt.Fprintf(writer, "<title>%s %s</title>\n", html.EscapeString(h.Layer.PrettyName), h.Kind)
+	fmt.Fprintf(writer, `<rect width="%d" height="%d" fill="%s"/>`+"\n", width, height, hexColor(heatmapBackground))
+
+	for row := range h.Rows {
+		for column := range h.Columns {
+			count := h.Count(column, row)
+			if count == 0 {
+				continue
+			}
+			fmt.Fprintf(writer, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" fill-opacity="0.8"><title>%d</title></rect>`+"\n",
+				float64(column)*binSize, float64(row)*binSize, binSize, binSize, hexColor(heatColor(count, maxCount)), count)
+		}
+	}
+
+	if err := h.Layer.WriteSVGLayout(writer, heatmapSquareSize); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(writer, "</svg>")
+	return writer.Flush()
+}
+
+// renders the heatmap with the grid and the strongpoints of the layer as PNG,
+// the standard library cannot draw text so the labels are left out
+func (h Heatmap) PNG(w io.Writer) error {
+	if h.Layer.Grid.Scale <= 0 {
+		return hll.ErrNoGrid
+	}
+	if h.Resolution < 1 {
+		return errHeatmapResolution
+	}
+	width, height := h.imageSize()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	maxCount := h.Max()
+	binSize := float64(heatmapSquareSize) / float64(h.Resolution)
+
+	for y := range height {
+		for x := range width {
+			pixel := heatmapBackground
+			if count := h.Count(int(float64(x)/binSize), int(float64(y)/binSize)); count > 0 {
+				pixel = blend(heatmapBackground, heatColor(count, maxCount), 0.8)
+			}
+			if x%heatmapSquareSize == 0 || y%heatmapSquareSize == 0 {
+				pixel = heatmapGridLine
+			}
+			img.SetRGBA(x, y, pixel)
+		}
+	}
+
+	for _, bounds := range h.sectorBounds() {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			img.SetRGBA(x, bounds.Min.Y, heatmapSector)
+			img.SetRGBA(x, bounds.Max.Y-1, heatmapSector)
+		}
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			img.SetRGBA(bounds.Min.X, y, heatmapSector)
+			img.SetRGBA(bounds.Max.X-1, y, heatmapSector)
+		}
+	}
+	for _, strongpoint := range h.Layer.Strongpoints() {
+		cx, cy := h.Layer.ToPixel(strongpoint.Center, heatmapSquareSize)
+		radius := strongpoint.Radius / h.Layer.Grid.Scale * heatmapSquareSize
+		// one point per pixel of the circumference keeps the outline closed
+		steps := max(int(2*math.Pi*radius), 8)
+		for i := range steps {
+			angle := 2 * math.Pi * float64(i) / float64(steps)
+			img.SetRGBA(int(cx+radius*math.Cos(angle)), int(cy+radius*math.Sin(angle)), heatmapStrongpoint)
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+func (h Heatmap) imageSize() (int, int) {
+	return h.Columns / h.Resolution * heatmapSquareSize, h.Rows / h.Resolution * heatmapSquareSize
+}
+
+// the pixel bounds of the sectors of the layer, the grid coordinates of a sector are inclusive
+func (h Heatmap) sectorBounds() []image.Rectangle {
+	sectors, err := h.Layer.Sectors()
+	if err != nil {
+		return []image.Rectangle{}
+	}
+	bounds := []image.Rectangle{}
+	for _, sector := range sectors {
+		bounds = append(bounds, image.Rect(
+			(sector.From.X-h.Layer.Grid.Min.X)*heatmapSquareSize,
+			(sector.From.Y-h.Layer.Grid.Min.Y)*heatmapSquareSize,
+			(sector.To.X-h.Layer.Grid.Min.X+1)*heatmapSquareSize,
+			(sector.To.Y-h.Layer.Grid.Min.Y+1)*heatmapSquareSize,
+		))
+	}
+	return bounds
+}
+
+// the square root keeps sparse bins visible next to hot spots
+func heatColor(count, maxCount int) color.RGBA {
+	if maxCount <= 0 {
+		return heatmapRamp[0]
+	}
+	intensity := math.Sqrt(float64(count) / float64(maxCount))
+	position := intensity * float64(len(heatmapRamp)-1)
+	index := min(int(position), len(heatmapRamp)-2)
+	return blend(heatmapRamp[index], heatmapRamp[index+1], position-float64(index))
+}
+
+func blend(from, to color.RGBA, ratio float64) color.RGBA {
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + (float64(b)-float64(a))*ratio))
+	}
+	return color.RGBA{R: mix(from.R, to.R), G: mix(from.G, to.G), B: mix(from.B, to.B), A: 0xff}
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
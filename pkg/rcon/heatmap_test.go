@@ -0,0 +1,167 @@
+package rcon
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestHeatmap(t *testing.T) {
+	layer := hll.LAYER_CARENTAN_WARFARE.Layer()
+	start := time.Unix(1700000000, 0).UTC()
+	at := func(seconds int, eventType hll.EventType) hll.GenericEvent {
+		return hll.GenericEvent{EventType: eventType, EventTime: start.Add(time.Duration(seconds) * time.Second)}
+	}
+	positioned := func(id string, x, y float64) hll.DetailedPlayerInfo {
+		return hll.DetailedPlayerInfo{PlayerInfo: hll.PlayerInfo{ID: id}, Position: hll.Position{X: x, Y: y}}
+	}
+	kill := func(seconds int, killer, victim hll.DetailedPlayerInfo) hll.KillEvent {
+		return hll.KillEvent{
+			GenericEvent: at(seconds, hll.EVENT_KILL),
+			Killer:       killer.PlayerInfo,
+			Victim:       victim.PlayerInfo,
+			Context:      hll.NewKillContext(killer, victim),
+		}
+	}
+	snapshot := func(seconds int, mapID string, players ...hll.DetailedPlayerInfo) hll.ServerSnapshotEvent {
+		return hll.ServerSnapshotEvent{GenericEvent: at(seconds, hll.EVENT_SERVER_SNAPSHOT), SessionInfo: hll.SessionInfo{MapID: mapID}, Players: players}
+	}
+
+	t.Run("binning", func(t *testing.T) {
+		heatmap := NewHeatmap(layer, HEATMAP_KILLS, 2)
+		if heatmap.Columns != 20 || heatmap.Rows != 20 {
+			t.Fatalf("Expected 20x20 bins, but got %dx%d", heatmap.Columns, heatmap.Rows)
+		}
+		// just left of and above the center of the map
+		heatmap.Add(hll.Position{X: -1, Y: -1})
+		heatmap.Add(hll.Position{X: -5000, Y: -5000})
+		heatmap.Add(hll.Position{X: 1, Y: 1})
+		if heatmap.Add(hll.Position{X: 500000, Y: 0}) {
+			t.Error("Expected positions outside of the grid to be ignored")
+		}
+		if heatmap.Add(hll.Position{}) {
+			t.Error("Expected unknown positions to be ignored")
+		}
+		if heatmap.Count(9, 9) != 2 || heatmap.Count(10, 10) != 1 || heatmap.Total != 3 || heatmap.Max() != 2 {
+			t.Errorf("Expected 2 positions in bin (9,9) and 1 in (10,10), but got %d and %d", heatmap.Count(9, 9), heatmap.Count(10, 10))
+		}
+	})
+
+	t.Run("recorder", func(t *testing.T) {
+		alice := positioned("1", -65000, -40000)
+		bob := positioned("2", 10000, 10000)
+
+		recorder := newHeatmapRecorder(1)
+		var finished []HeatmapSet
+		recorder.OnMatchEnd(func(set HeatmapSet) {
+			finished = append(finished, set)
+		})
+		if _, ok := recorder.Current(); ok {
+			t.Error("Expected no heatmaps before the layer is known")
+		}
+
+		events := []hll.Event{
+			snapshot(0, string(layer.ID), alice, bob),
+			kill(10, alice, bob),
+			kill(20, alice, bob),
+			hll.MatchEndEvent{GenericEvent: at(30, hll.EVENT_MATCHEND)},
+			// after the end of the match
+			kill(40, bob, alice),
+		}
+		for _, event := range events {
+			recorder.Notify(event)
+		}
+
+		if len(finished) != 1 {
+			t.Fatalf("Expected 1 finished match, but got %d", len(finished))
+		}
+		set := finished[0]
+		if set.Layer.ID != layer.ID || !set.End.Equal(start.Add(30*time.Second)) {
+			t.Errorf("Expected the match on %s to end at %v, but got %s at %v", layer.ID, start.Add(30*time.Second), set.Layer.ID, set.End)
+		}
+		if set.Kills.Total != 2 || set.Kills.Count(1, 3) != 2 {
+			t.Errorf("Expected 2 kills in bin (1,3), but got %d of %d", set.Kills.Count(1, 3), set.Kills.Total)
+		}
+		if set.Deaths.Total != 2 || set.Deaths.Count(5, 5) != 2 {
+			t.Errorf("Expected 2 deaths in bin (5,5), but got %d of %d", set.Deaths.Count(5, 5), set.Deaths.Total)
+		}
+		if set.Density.Total != 2 {
+			t.Errorf("Expected 2 sampled positions, but got %d", set.Density.Total)
+		}
+
+		recorder.Notify(snapshot(50, string(hll.LAYER_FOY_WARFARE), alice))
+		if current, _ := recorder.Current(); current.Layer.ID != hll.LAYER_FOY_WARFARE || current.Density.Total != 1 {
+			t.Errorf("Expected new heatmaps for the next layer, but got %s with %d positions", current.Layer.ID, current.Density.Total)
+		}
+	})
+
+	t.Run("journal", func(t *testing.T) {
+		alice := positioned("1", -65000, -40000)
+		bob := positioned("2", 10000, 10000)
+		dir := t.TempDir()
+		writeJournal(t, JournalConfig{Directory: dir}, []hll.Event{
+			kill(0, alice, bob), // the layer is not known yet
+			snapshot(10, string(layer.ID), alice, bob),
+			kill(20, alice, bob),
+			snapshot(30, string(hll.LAYER_FOY_WARFARE), alice, bob),
+			kill(40, alice, bob),
+			snapshot(50, string(layer.ID), alice, bob),
+			kill(70, bob, alice), // after the end of the period
+			kill(60, bob, alice), // logged after the previous kill
+		})
+
+		set, err := JournalHeatmaps(dir, layer.ID, time.Time{}, start.Add(65*time.Second), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if set.Kills.Total != 2 || set.Deaths.Total != 2 || set.Density.Total != 4 {
+			t.Errorf("Expected 2 kills, 2 deaths and 4 positions, but got %d, %d and %d", set.Kills.Total, set.Deaths.Total, set.Density.Total)
+		}
+		if !set.Start.Equal(start.Add(10*time.Second)) || !set.End.Equal(start.Add(65*time.Second)) {
+			t.Errorf("Expected the period from %v to %v, but got %v to %v", start.Add(10*time.Second), start.Add(65*time.Second), set.Start, set.End)
+		}
+
+		if _, err := JournalHeatmaps(dir, "unknown", time.Time{}, time.Time{}, 1); err == nil {
+			t.Error("Expected an error for an unknown layer")
+		}
+	})
+
+	t.Run("rendering", func(t *testing.T) {
+		heatmap := NewHeatmap(layer, HEATMAP_DEATHS, 4)
+		heatmap.Add(hll.Position{X: 10000, Y: 10000})
+
+		var svg bytes.Buffer
+		if err := heatmap.SVG(&svg); err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range []string{"<svg", "Blactot", ">J<", ">10<", "</svg>"} {
+			if !strings.Contains(svg.String(), expected) {
+				t.Errorf("Expected the SVG to contain %q", expected)
+			}
+		}
+
+		var image bytes.Buffer
+		if err := heatmap.PNG(&image); err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := png.Decode(&image)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size := decoded.Bounds().Size(); size.X != 640 || size.Y != 640 {
+			t.Errorf("Expected a 640x640 image, but got %v", size)
+		}
+
+		if err := (Heatmap{}).SVG(&svg); !errors.Is(err, hll.ErrNoGrid) {
+			t.Errorf("Expected %v for a heatmap without a grid, but got %v", hll.ErrNoGrid, err)
+		}
+		if err := (Heatmap{}).PNG(&image); !errors.Is(err, hll.ErrNoGrid) {
+			t.Errorf("Expected %v for a heatmap without a grid, but got %v", hll.ErrNoGrid, err)
+		}
+	})
+}
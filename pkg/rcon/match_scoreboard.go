@@ -0,0 +1,115 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+// the final statistics of a match, the players are ordered by kills
+type Scoreboard struct {
+	Map     hll.Map
+	Start   time.Time
+	End     time.Time
+	Result  hll.TeamData
+	Players []PlayerMatchStats
+}
+
+func (sb Scoreboard) Duration() time.Duration {
+	return sb.End.Sub(sb.Start)
+}
+
+func (sb Scoreboard) Player(playerID string) (PlayerMatchStats, bool) {
+	for _, player := range sb.Players {
+		if player.Player.ID == playerID {
+			return player, true
+		}
+	}
+	return PlayerMatchStats{}, false
+}
+
+// the weapon the player killed the most with
+func (pms PlayerMatchStats) TopWeapon() (string, int) {
+	name, kills := "", 0
+	for weapon, count := range pms.WeaponKills {
+		if count > kills || (count == kills && weapon < name) {
+			name, kills = weapon, count
+		}
+	}
+	return name, kills
+}
+
+func (sb Scoreboard) JSON() ([]byte, error) {
+	return json.MarshalIndent(sb, "", "  ")
+}
+
+var scoreboardColumns = []string{
+	"Name", "ID", "Team", "Kills", "Deaths", "K/D", "Team Kills", "Longest Streak",
+	"Top Weapon", "Most Killed", "Nemesis", "Playtime", "Combat", "Offense", "Defense", "Support",
+}
+
+func (sb Scoreboard) rows() [][]string {
+	rows := [][]string{}
+	for _, player := range sb.Players {
+		weapon, _ := player.TopWeapon()
+		rows = append(rows, []string{
+			player.Player.Name,
+			player.Player.ID,
+			string(player.Team),
+			strconv.Itoa(player.Kills),
+			strconv.Itoa(player.Deaths),
+			strconv.FormatFloat(player.KillDeathRatio, 'f', 2, 64),
+			strconv.Itoa(player.TeamKills),
+			strconv.Itoa(player.LongestStreak),
+			weapon,
+			player.MostKilled.Name,
+			player.Nemesis.Name,
+			player.Playtime.Truncate(time.Second).String(),
+			strconv.Itoa(player.Score.Combat),
+			strconv.Itoa(player.Score.Offense),
+			strconv.Itoa(player.Score.Defense),
+			strconv.Itoa(player.Score.Support),
+		})
+	}
+	return rows
+}
+
+func (sb Scoreboard) CSV() ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(scoreboardColumns); err != nil {
+		return nil, err
+	}
+	if err := writer.WriteAll(sb.rows()); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (sb Scoreboard) Markdown() string {
+	var builder strings.Builder
+
+	title := sb.Map.PrettyName
+	if title == "" {
+		title = "Match"
+	}
+	fmt.Fprintf(&builder, "## %s\n\n", title)
+	fmt.Fprintf(&builder, "Allies %d - %d Axis, %s\n\n", sb.Result.Allies, sb.Result.Axis, sb.Duration().Truncate(time.Second))
+
+	escape := strings.NewReplacer("|", "\\|", "\n", " ")
+	fmt.Fprintf(&builder, "| %s |\n", strings.Join(scoreboardColumns, " | "))
+	fmt.Fprintf(&builder, "|%s\n", strings.Repeat(" --- |", len(scoreboardColumns)))
+	for _, row := range sb.rows() {
+		for i := range row {
+			row[i] = escape.Replace(row[i])
+		}
+		fmt.Fprintf(&builder, "| %s |\n", strings.Join(row, " | "))
+	}
+	return builder.String()
+}
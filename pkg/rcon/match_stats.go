@@ -0,0 +1,261 @@
+package rcon
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+type PlayerMatchStats struct {
+	Player          hll.PlayerInfo
+	Team            hll.TeamIdentifier
+	Kills           int
+	Deaths          int
+	TeamKills       int
+	KillDeathRatio  float64
+	WeaponKills     map[string]int         // kills per weapon name
+	WeaponTypeKills map[hll.WeaponType]int // kills per weapon type
+	LongestStreak   int
+	MostKilled      hll.PlayerInfo // the victim this player killed most often
+	MostKilledCount int
+	Nemesis         hll.PlayerInfo // the player who killed this player most often
+	NemesisKills    int
+	Playtime        time.Duration
+	Score           hll.Score
+}
+
+// aggregates the events of one match into a scoreboard, finalized when the match ends
+type MatchStats struct {
+	start     time.Time
+	gameMap   hll.Map
+	players   map[string]*playerMatchTracker
+	last      *Scoreboard
+	callbacks []func(Scoreboard)
+	mutex     sync.Mutex
+}
+
+type playerMatchTracker struct {
+	stats   PlayerMatchStats
+	streak  int
+	victims map[string]int
+	killers map[string]int
+	names   map[string]hll.PlayerInfo
+	joined  time.Time // zero while the player is not on the server
+}
+
+// subscribes the stats to the events of the rcon
+func NewMatchStats(rcn *Rcon) (*MatchStats, error) {
+	if !rcn.Events.enabled {
+		return nil, errEventsDisabled
+	}
+	stats := newMatchStats()
+	rcn.Events.Register(stats)
+	return stats, nil
+}
+
+func newMatchStats() *MatchStats {
+	return &MatchStats{
+		players: make(map[string]*playerMatchTracker),
+	}
+}
+
+// the callback receives the scoreboard of every finished match
+func (ms *MatchStats) OnFinalized(callback func(Scoreboard)) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	ms.callbacks = append(ms.callbacks, callback)
+}
+
+// the scoreboard of the running match up to now
+func (ms *MatchStats) Current() Scoreboard {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	return ms.scoreboard(time.Now(), hll.TeamData{})
+}
+
+// the scoreboard of the last finished match
+func (ms *MatchStats) Last() (Scoreboard, bool) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	if ms.last == nil {
+		return Scoreboard{}, false
+	}
+	return *ms.last, true
+}
+
+func (ms *MatchStats) Notify(event hll.Event) {
+	ms.mutex.Lock()
+	if ms.start.IsZero() {
+		// started in the middle of a match
+		ms.start = event.Time()
+	}
+
+	var finalized *Scoreboard
+	switch e := event.(type) {
+	case hll.MatchStartEvent:
+		ms.reset(e.EventTime, e.Map)
+	case hll.MatchEndEvent:
+		if e.Map.ID != "" {
+			ms.gameMap = e.Map
+		}
+		scoreboard := ms.scoreboard(e.EventTime, e.Score)
+		ms.last = &scoreboard
+		finalized = &scoreboard
+		ms.reset(e.EventTime, hll.Map{})
+	case hll.ConnectEvent:
+		ms.join(ms.track(e.Player, e.EventTime), e.EventTime)
+	case hll.DisconnectEvent:
+		if pt, ok := ms.players[e.Player.ID]; ok && !pt.joined.IsZero() {
+			pt.stats.Playtime += e.EventTime.Sub(pt.joined)
+			pt.joined = time.Time{}
+		}
+	case hll.KillEvent:
+		killer := ms.track(e.Killer, ms.start)
+		killer.stats.Kills++
+		killer.stats.WeaponKills[e.Weapon.Name]++
+		killer.stats.WeaponTypeKills[e.Weapon.Type]++
+		killer.streak++
+		killer.stats.LongestStreak = max(killer.stats.LongestStreak, killer.streak)
+		killer.victims[e.Victim.ID]++
+		killer.names[e.Victim.ID] = e.Victim
+		if e.Context != nil {
+			killer.stats.Team = e.Context.Killer.Team
+		}
+	case hll.DeathEvent:
+		victim := ms.track(e.Victim, ms.start)
+		victim.stats.Deaths++
+		victim.streak = 0
+		victim.killers[e.Killer.ID]++
+		victim.names[e.Killer.ID] = e.Killer
+		if e.Context != nil {
+			victim.stats.Team = e.Context.Victim.Team
+		}
+	case hll.TeamKillEvent:
+		killer := ms.track(e.Killer, ms.start)
+		killer.stats.TeamKills++
+	case hll.TeamDeathEvent:
+		victim := ms.track(e.Victim, ms.start)
+		victim.stats.Deaths++
+		victim.streak = 0
+	case hll.PlayerScoreUpdateEvent:
+		ms.track(e.Player, ms.start).stats.Score = e.NewScore
+	case hll.PlayerSwitchTeamEvent:
+		ms.track(e.Player, ms.start).stats.Team = e.NewTeam
+	case hll.ServerSnapshotEvent:
+		for _, player := range e.Players {
+			pt := ms.track(player.PlayerInfo, ms.start)
+			ms.join(pt, e.EventTime)
+			pt.stats.Team = player.Team
+			pt.stats.Score = player.Score
+		}
+	}
+	callbacks := slices.Clone(ms.callbacks)
+	ms.mutex.Unlock()
+
+	if finalized != nil {
+		for _, callback := range callbacks {
+			callback(*finalized)
+		}
+	}
+}
+
+// returns the tracker of the player, players seen for the first time are assumed to be on the server since the given time;
+// the playtime of players that left is only continued by join, since log events may arrive after the disconnect
+func (ms *MatchStats) track(player hll.PlayerInfo, since time.Time) *playerMatchTracker {
+	pt, ok := ms.players[player.ID]
+	if !ok {
+		pt = newPlayerMatchTracker(player, hll.TEAM_NONE, since)
+		ms.players[player.ID] = pt
+	}
+	if player.Name != "" {
+		pt.stats.Player.Name = player.Name
+	}
+	return pt
+}
+
+// continues the playtime of a player that is known to be on the server again
+func (ms *MatchStats) join(pt *playerMatchTracker, at time.Time) {
+	if pt.joined.IsZero() {
+		pt.joined = at
+	}
+}
+
+// players that are still on the server are carried over into the next match
+func (ms *MatchStats) reset(start time.Time, gameMap hll.Map) {
+	players := make(map[string]*playerMatchTracker)
+	for id, pt := range ms.players {
+		if !pt.joined.IsZero() {
+			players[id] = newPlayerMatchTracker(pt.stats.Player, pt.stats.Team, start)
+		}
+	}
+	ms.players = players
+	ms.start = start
+	ms.gameMap = gameMap
+}
+
+func newPlayerMatchTracker(player hll.PlayerInfo, team hll.TeamIdentifier, joined time.Time) *playerMatchTracker {
+	return &playerMatchTracker{
+		stats: PlayerMatchStats{
+			Player:          player,
+			Team:            team,
+			WeaponKills:     make(map[string]int),
+			WeaponTypeKills: make(map[hll.WeaponType]int),
+		},
+		victims: make(map[string]int),
+		killers: make(map[string]int),
+		names:   make(map[string]hll.PlayerInfo),
+		joined:  joined,
+	}
+}
+
+func (ms *MatchStats) scoreboard(end time.Time, result hll.TeamData) Scoreboard {
+	players := []PlayerMatchStats{}
+	for _, pt := range ms.players {
+		stats := pt.stats
+		stats.WeaponKills = maps.Clone(stats.WeaponKills)
+		stats.WeaponTypeKills = maps.Clone(stats.WeaponTypeKills)
+		stats.KillDeathRatio = float64(stats.Kills) / float64(max(stats.Deaths, 1))
+		if !pt.joined.IsZero() && end.After(pt.joined) {
+			stats.Playtime += end.Sub(pt.joined)
+		}
+		stats.MostKilled, stats.MostKilledCount = mostFrequent(pt.victims, pt.names)
+		stats.Nemesis, stats.NemesisKills = mostFrequent(pt.killers, pt.names)
+		players = append(players, stats)
+	}
+
+	slices.SortFunc(players, func(a, b PlayerMatchStats) int {
+		if a.Kills != b.Kills {
+			return b.Kills - a.Kills
+		}
+		if a.Deaths != b.Deaths {
+			return a.Deaths - b.Deaths
+		}
+		return strings.Compare(a.Player.Name, b.Player.Name)
+	})
+
+	return Scoreboard{
+		Map:     ms.gameMap,
+		Start:   ms.start,
+		End:     end,
+		Result:  result,
+		Players: players,
+	}
+}
+
+// ties are broken by the player ID, so the result does not depend on the map order
+func mostFrequent(counts map[string]int, names map[string]hll.PlayerInfo) (hll.PlayerInfo, int) {
+	bestID, bestCount := "", 0
+	for id, count := range counts {
+		if count > bestCount || (count == bestCount && id < bestID) {
+			bestID, bestCount = id, count
+		}
+	}
+	if bestCount == 0 {
+		return hll.PlayerInfo{}, 0
+	}
+	return names[bestID], bestCount
+}
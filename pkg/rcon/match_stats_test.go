@@ -0,0 +1,149 @@
+package rcon
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestMatchStats(t *testing.T) {
+	start := time.Unix(1700000000, 0).UTC()
+	at := func(minutes int) hll.GenericEvent {
+		return hll.GenericEvent{EventTime: start.Add(time.Duration(minutes) * time.Minute)}
+	}
+	with := func(ge hll.GenericEvent, eventType hll.EventType) hll.GenericEvent {
+		ge.EventType = eventType
+		return ge
+	}
+
+	alice := hll.PlayerInfo{Name: "Alice", ID: "1"}
+	bob := hll.PlayerInfo{Name: "Bob", ID: "2"}
+	carol := hll.PlayerInfo{Name: "Carol", ID: "3"}
+	rifle := hll.Weapon{Name: "M1 GARAND", Type: hll.WEAPON_TYPE_SEMI_AUTO_RIFLE}
+	mg := hll.Weapon{Name: "MG42", Type: hll.WEAPON_TYPE_MACHINE_GUN}
+
+	kill := func(minute int, killer, victim hll.PlayerInfo, weapon hll.Weapon) []hll.Event {
+		return []hll.Event{
+			hll.KillEvent{GenericEvent: with(at(minute), hll.EVENT_KILL), Killer: killer, Victim: victim, Weapon: weapon},
+			hll.DeathEvent{GenericEvent: with(at(minute), hll.EVENT_DEATH), Killer: killer, Victim: victim, Weapon: weapon},
+		}
+	}
+
+	events := []hll.Event{
+		hll.MatchStartEvent{GenericEvent: with(at(0), hll.EVENT_MATCHSTART), Map: hll.Map{PrettyName: "Carentan"}},
+		hll.PlayerSwitchTeamEvent{GenericEvent: with(at(0), hll.EVENT_TEAM_SWITCHED), Player: alice, NewTeam: hll.TEAM_ALLIES},
+		hll.ConnectEvent{GenericEvent: with(at(10), hll.EVENT_CONNECTED), Player: carol},
+	}
+	events = append(events, kill(11, alice, bob, rifle)...)
+	events = append(events, kill(12, alice, bob, rifle)...)
+	events = append(events, kill(13, alice, carol, mg)...)
+	events = append(events, kill(14, bob, alice, mg)...)
+	events = append(events,
+		hll.TeamKillEvent{GenericEvent: with(at(15), hll.EVENT_TEAMKILL), Killer: bob, Victim: carol},
+		hll.PlayerScoreUpdateEvent{GenericEvent: with(at(16), hll.EVENT_SCORE_UPDATE), Player: alice, NewScore: hll.Score{Combat: 30, Offense: 20}},
+		hll.DisconnectEvent{GenericEvent: with(at(40), hll.EVENT_DISCONNECTED), Player: carol},
+		hll.MatchEndEvent{GenericEvent: with(at(90), hll.EVENT_MATCHEND), Score: hll.TeamData{Allies: 5, Axis: 0}},
+	)
+
+	stats := newMatchStats()
+	var finalized []Scoreboard
+	stats.OnFinalized(func(sb Scoreboard) {
+		finalized = append(finalized, sb)
+	})
+	for _, event := range events {
+		stats.Notify(event)
+	}
+
+	if len(finalized) != 1 {
+		t.Fatalf("Expected 1 finalized scoreboard, but got %d", len(finalized))
+	}
+	scoreboard := finalized[0]
+	if last, ok := stats.Last(); !ok || last.End != scoreboard.End {
+		t.Error("Expected the last scoreboard to be kept")
+	}
+	if scoreboard.Map.PrettyName != "Carentan" || scoreboard.Result.Allies != 5 || scoreboard.Duration() != 90*time.Minute {
+		t.Errorf("Unexpected match details %+v", scoreboard)
+	}
+	if len(scoreboard.Players) != 3 || scoreboard.Players[0].Player.ID != alice.ID {
+		t.Fatalf("Expected 3 players with Alice first, but got %+v", scoreboard.Players)
+	}
+
+	a := scoreboard.Players[0]
+	if a.Kills != 3 || a.Deaths != 1 || a.KillDeathRatio != 3 || a.LongestStreak != 3 {
+		t.Errorf("Unexpected kill stats %+v", a)
+	}
+	if a.WeaponKills["M1 GARAND"] != 2 || a.WeaponTypeKills[hll.WEAPON_TYPE_MACHINE_GUN] != 1 {
+		t.Errorf("Unexpected weapon breakdown %v / %v", a.WeaponKills, a.WeaponTypeKills)
+	}
+	if a.MostKilled != bob || a.MostKilledCount != 2 || a.Nemesis != bob {
+		t.Errorf("Expected Bob as most killed and nemesis, but got %v and %v", a.MostKilled, a.Nemesis)
+	}
+	if a.Team != hll.TEAM_ALLIES || a.Score.Combat != 30 || a.Playtime != 90*time.Minute {
+		t.Errorf("Unexpected team, score or playtime %+v", a)
+	}
+
+	c, _ := scoreboard.Player(carol.ID)
+	if c.Playtime != 30*time.Minute || c.Deaths != 1 {
+		t.Errorf("Expected Carol to play 30 minutes and die once, but got %v and %d", c.Playtime, c.Deaths)
+	}
+	b, _ := scoreboard.Player(bob.ID)
+	if b.TeamKills != 1 || b.KillDeathRatio != 0.5 {
+		t.Errorf("Expected Bob to have a team kill and 0.5 K/D, but got %d and %v", b.TeamKills, b.KillDeathRatio)
+	}
+
+	t.Run("renderers", func(t *testing.T) {
+		data, err := scoreboard.JSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded Scoreboard
+		if err := json.Unmarshal(data, &decoded); err != nil || len(decoded.Players) != 3 {
+			t.Errorf("Expected the scoreboard to round trip, but got %v", err)
+		}
+
+		csv, err := scoreboard.CSV()
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(csv)), "\n")
+		if len(lines) != 4 || !strings.HasPrefix(lines[1], "Alice,1,Allies,3,1,3.00,0,3,M1 GARAND,Bob,Bob,1h30m0s,30,20,0,0") {
+			t.Errorf("Unexpected CSV %q", csv)
+		}
+
+		markdown := scoreboard.Markdown()
+		if !strings.Contains(markdown, "## Carentan") || !strings.Contains(markdown, "| Alice | 1 | Allies | 3 |") {
+			t.Errorf("Unexpected markdown %q", markdown)
+		}
+	})
+
+	t.Run("next match", func(t *testing.T) {
+		current := stats.Current()
+		for _, player := range current.Players {
+			if player.Kills != 0 || player.Player.ID == carol.ID {
+				t.Errorf("Expected a fresh match without disconnected players, but got %+v", player)
+			}
+		}
+	})
+
+	t.Run("late kills after a disconnect", func(t *testing.T) {
+		stats := newMatchStats()
+		stats.Notify(hll.MatchStartEvent{GenericEvent: with(at(0), hll.EVENT_MATCHSTART)})
+		stats.Notify(hll.ConnectEvent{GenericEvent: with(at(10), hll.EVENT_CONNECTED), Player: carol})
+		stats.Notify(hll.DisconnectEvent{GenericEvent: with(at(40), hll.EVENT_DISCONNECTED), Player: carol})
+		// the kill happened before the disconnect, but its log arrived afterwards
+		for _, event := range kill(39, carol, bob, rifle) {
+			stats.Notify(event)
+		}
+		stats.Notify(hll.MatchEndEvent{GenericEvent: with(at(90), hll.EVENT_MATCHEND)})
+
+		last, _ := stats.Last()
+		for _, player := range last.Players {
+			if player.Player.ID == carol.ID && (player.Playtime != 30*time.Minute || player.Kills != 1) {
+				t.Errorf("Expected Carol to play 30 minutes with one kill, but got %v and %d", player.Playtime, player.Kills)
+			}
+		}
+	})
+}
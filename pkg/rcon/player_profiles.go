@@ -0,0 +1,311 @@
+package rcon
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+	"github.com/zMoooooritz/go-let-loose/pkg/logger"
+)
+
+// a name or clan tag a player used during the given period
+type NameRecord struct {
+	Name      string
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+type PlayerProfile struct {
+	ID        string
+	Platform  hll.PlayerPlatform
+	Names     []NameRecord // oldest first, the last entry is the current name
+	ClanTags  []NameRecord // oldest first, the last entry is the current clan tag
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Playtime  time.Duration // summed over all sessions, the copies handed out include the running one
+	Sessions  int
+	Kills     int
+	Deaths    int
+}
+
+func (pp PlayerProfile) Name() string {
+	if len(pp.Names) == 0 {
+		return ""
+	}
+	return pp.Names[len(pp.Names)-1].Name
+}
+
+func (pp PlayerProfile) ClanTag() string {
+	if len(pp.ClanTags) == 0 {
+		return ""
+	}
+	return pp.ClanTags[len(pp.ClanTags)-1].Name
+}
+
+// the name the player used at the given time
+func (pp PlayerProfile) NameAt(at time.Time) (string, bool) {
+	return recordAt(pp.Names, at)
+}
+
+// the clan tag the player used at the given time
+func (pp PlayerProfile) ClanTagAt(at time.Time) (string, bool) {
+	return recordAt(pp.ClanTags, at)
+}
+
+func recordAt(records []NameRecord, at time.Time) (string, bool) {
+	for i := len(records) - 1; i >= 0; i-- {
+		if !records[i].FirstSeen.After(at) {
+			return records[i].Name, true
+		}
+	}
+	return "", false
+}
+
+// keeps a persistent profile of every player that has been seen on the server
+type ProfileStore struct {
+	backend  ProfileBackend
+	profiles map[string]*PlayerProfile
+	online   map[string]time.Time // the start of the running session per player
+	mutex    sync.Mutex
+}
+
+// subscribes the store to the events of the rcon, the profiles are loaded from and saved to the backend
+func NewProfileStore(rcn *Rcon, backend ProfileBackend) (*ProfileStore, error) {
+	if !rcn.Events.enabled {
+		return nil, errEventsDisabled
+	}
+	store, err := newProfileStore(backend)
+	if err != nil {
+		return nil, err
+	}
+	rcn.Events.Register(store)
+	return store, nil
+}
+
+func newProfileStore(backend ProfileBackend) (*ProfileStore, error) {
+	profiles, err := backend.Load()
+	if err != nil {
+		return nil, err
+	}
+	ps := &ProfileStore{
+		backend:  backend,
+		profiles: make(map[string]*PlayerProfile),
+		online:   make(map[string]time.Time),
+	}
+	for _, profile := range profiles {
+		ps.profiles[profile.ID] = &profile
+	}
+	return ps, nil
+}
+
+// closes the running sessions and the backend
+func (ps *ProfileStore) Close() error {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	now := time.Now()
+	changed := map[string]struct{}{}
+	for id := range ps.online {
+		ps.endSession(id, now)
+		changed[id] = struct{}{}
+	}
+	ps.save(changed)
+	return ps.backend.Close()
+}
+
+func (ps *ProfileStore) Profile(id string) (PlayerProfile, bool) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	profile, ok := ps.profiles[id]
+	if !ok {
+		return PlayerProfile{}, false
+	}
+	return ps.snapshot(profile), true
+}
+
+// all known profiles ordered by the time they were last seen, most recent first
+func (ps *ProfileStore) Profiles() []PlayerProfile {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	profiles := []PlayerProfile{}
+	for _, profile := range ps.profiles {
+		profiles = append(profiles, ps.snapshot(profile))
+	}
+	slices.SortFunc(profiles, func(a, b PlayerProfile) int {
+		return b.LastSeen.Compare(a.LastSeen)
+	})
+	return profiles
+}
+
+// the profiles of all players that ever used a name containing the query, case insensitive
+func (ps *ProfileStore) FindByName(query string) []PlayerProfile {
+	query = strings.ToLower(query)
+	profiles := []PlayerProfile{}
+	for _, profile := range ps.Profiles() {
+		if slices.ContainsFunc(profile.Names, func(record NameRecord) bool {
+			return strings.Contains(strings.ToLower(record.Name), query)
+		}) {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// the profiles of all players that ever used the given clan tag, case insensitive
+func (ps *ProfileStore) FindByClanTag(clanTag string) []PlayerProfile {
+	profiles := []PlayerProfile{}
+	for _, profile := range ps.Profiles() {
+		if slices.ContainsFunc(profile.ClanTags, func(record NameRecord) bool {
+			return strings.EqualFold(record.Name, clanTag)
+		}) {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// the name the player used at the given time
+func (ps *ProfileStore) NameAt(id string, at time.Time) (string, bool) {
+	profile, ok := ps.Profile(id)
+	if !ok {
+		return "", false
+	}
+	return profile.NameAt(at)
+}
+
+func (ps *ProfileStore) Notify(event hll.Event) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	changed := map[string]struct{}{}
+	touch := func(player hll.PlayerInfo) *PlayerProfile {
+		changed[player.ID] = struct{}{}
+		return ps.touch(player, event.Time())
+	}
+
+	switch e := event.(type) {
+	case hll.ConnectEvent:
+		touch(e.Player)
+		ps.startSession(e.Player.ID, e.EventTime)
+	case hll.DisconnectEvent:
+		touch(e.Player)
+		ps.endSession(e.Player.ID, e.EventTime)
+	case hll.KillEvent:
+		touch(e.Killer).Kills++
+		touch(e.Victim)
+	case hll.DeathEvent:
+		touch(e.Victim).Deaths++
+	case hll.TeamDeathEvent:
+		touch(e.Victim).Deaths++
+	case hll.PlayerClanTagChangedEvent:
+		profile := touch(e.Player)
+		profile.ClanTags = observeClanTag(profile.ClanTags, e.NewClanTag, e.EventTime)
+	case hll.ServerSnapshotEvent:
+		present := map[string]struct{}{}
+		for _, player := range e.Players {
+			present[player.ID] = struct{}{}
+			profile := touch(player.PlayerInfo)
+			profile.ClanTags = observeClanTag(profile.ClanTags, player.ClanTag, e.EventTime)
+			if player.Platform != "" && player.Platform != hll.PLAYER_PLATFORM_NONE {
+				profile.Platform = player.Platform
+			}
+			// the connect was missed, e.g. the player joined before the events were started
+			ps.startSession(player.ID, e.EventTime)
+		}
+		// the disconnect was missed
+		for id := range ps.online {
+			if _, ok := present[id]; !ok {
+				changed[id] = struct{}{}
+				ps.endSession(id, e.EventTime)
+			}
+		}
+	}
+
+	ps.save(changed)
+}
+
+func (ps *ProfileStore) touch(player hll.PlayerInfo, at time.Time) *PlayerProfile {
+	profile, ok := ps.profiles[player.ID]
+	if !ok {
+		profile = &PlayerProfile{ID: player.ID, Platform: hll.PLAYER_PLATFORM_NONE, FirstSeen: at}
+		ps.profiles[player.ID] = profile
+	}
+	if at.After(profile.LastSeen) {
+		profile.LastSeen = at
+	}
+	profile.Names = observeName(profile.Names, player.Name, at)
+	return profile
+}
+
+func (ps *ProfileStore) startSession(id string, at time.Time) {
+	if _, ok := ps.online[id]; ok {
+		return
+	}
+	ps.online[id] = at
+	ps.profiles[id].Sessions++
+}
+
+func (ps *ProfileStore) endSession(id string, at time.Time) {
+	start, ok := ps.online[id]
+	if !ok {
+		return
+	}
+	delete(ps.online, id)
+	if at.After(start) {
+		ps.profiles[id].Playtime += at.Sub(start)
+	}
+	if at.After(ps.profiles[id].LastSeen) {
+		ps.profiles[id].LastSeen = at
+	}
+}
+
+// the running session is included in the playtime of the returned copy
+func (ps *ProfileStore) snapshot(profile *PlayerProfile) PlayerProfile {
+	copied := *profile
+	copied.Names = slices.Clone(profile.Names)
+	copied.ClanTags = slices.Clone(profile.ClanTags)
+	if start, ok := ps.online[profile.ID]; ok {
+		copied.Playtime += max(time.Since(start), 0)
+	}
+	return copied
+}
+
+func (ps *ProfileStore) save(changed map[string]struct{}) {
+	if len(changed) == 0 {
+		return
+	}
+	profiles := []PlayerProfile{}
+	for id := range changed {
+		if profile, ok := ps.profiles[id]; ok {
+			profiles = append(profiles, *profile)
+		}
+	}
+	if err := ps.backend.Save(profiles...); err != nil {
+		logger.Error("saving player profiles failed:", err)
+	}
+}
+
+// extends the current record or starts a new one if the name changed
+func observeName(records []NameRecord, name string, at time.Time) []NameRecord {
+	if name == "" {
+		return records
+	}
+	if len(records) > 0 && records[len(records)-1].Name == name {
+		last := &records[len(records)-1]
+		if at.After(last.LastSeen) {
+			last.LastSeen = at
+		}
+		return records
+	}
+	return append(records, NameRecord{Name: name, FirstSeen: at, LastSeen: at})
+}
+
+// unlike names a clan tag may be removed, which is recorded as an empty tag
+func observeClanTag(records []NameRecord, clanTag string, at time.Time) []NameRecord {
+	if clanTag == "" && len(records) > 0 && records[len(records)-1].Name != "" {
+		return append(records, NameRecord{FirstSeen: at, LastSeen: at})
+	}
+	return observeName(records, clanTag, at)
+}
@@ -0,0 +1,170 @@
+package rcon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+)
+
+// the storage of the player profiles
+type ProfileBackend interface {
+	Load() ([]PlayerProfile, error)
+	Save(profiles ...PlayerProfile) error
+	Close() error
+}
+
+// compaction is not worth it below this amount of outdated records
+const minProfileCompaction = 1024
+
+// an embedded backend storing the profiles as an append only JSON lines file
+// the latest record of a player wins, outdated records are dropped by compaction
+type FileProfileBackend struct {
+	path    string
+	file    *os.File
+	writer  *bufio.Writer
+	live    map[string]struct{}
+	records int
+	mutex   sync.Mutex
+}
+
+// opens the file at the given path, it is created if it does not exist yet
+func NewFileProfileBackend(path string) (*FileProfileBackend, error) {
+	fpb := &FileProfileBackend{path: path}
+	if err := fpb.compact(); err != nil {
+		return nil, err
+	}
+	return fpb, nil
+}
+
+func (fpb *FileProfileBackend) Load() ([]PlayerProfile, error) {
+	fpb.mutex.Lock()
+	defer fpb.mutex.Unlock()
+	if err := fpb.writer.Flush(); err != nil {
+		return nil, err
+	}
+	return readProfiles(fpb.path)
+}
+
+func (fpb *FileProfileBackend) Save(profiles ...PlayerProfile) error {
+	fpb.mutex.Lock()
+	defer fpb.mutex.Unlock()
+	if fpb.file == nil {
+		return os.ErrClosed
+	}
+
+	for _, profile := range profiles {
+		data, err := json.Marshal(profile)
+		if err != nil {
+			return err
+		}
+		if _, err := fpb.writer.Write(append(data, '\n')); err != nil {
+			return err
+		}
+		fpb.live[profile.ID] = struct{}{}
+		fpb.records++
+	}
+	if err := fpb.writer.Flush(); err != nil {
+		return err
+	}
+
+	if fpb.records-len(fpb.live) > max(len(fpb.live), minProfileCompaction) {
+		return fpb.compact()
+	}
+	return nil
+}
+
+func (fpb *FileProfileBackend) Close() error {
+	fpb.mutex.Lock()
+	defer fpb.mutex.Unlock()
+	if fpb.file == nil {
+		return nil
+	}
+	err := errors.Join(fpb.writer.Flush(), fpb.file.Close())
+	fpb.file = nil
+	return err
+}
+
+// rewrites the file with only the latest record of each player and reopens it for appending
+func (fpb *FileProfileBackend) compact() error {
+	if fpb.file != nil {
+		if err := errors.Join(fpb.writer.Flush(), fpb.file.Close()); err != nil {
+			return err
+		}
+		fpb.file = nil
+	}
+
+	profiles, err := readProfiles(fpb.path)
+	if err != nil {
+		return err
+	}
+	if err := writeProfiles(fpb.path+".tmp", profiles); err != nil {
+		return err
+	}
+	if err := os.Rename(fpb.path+".tmp", fpb.path); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(fpb.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	fpb.file = file
+	fpb.writer = bufio.NewWriter(file)
+	fpb.live = make(map[string]struct{})
+	for _, profile := range profiles {
+		fpb.live[profile.ID] = struct{}{}
+	}
+	fpb.records = len(profiles)
+	return nil
+}
+
+func readProfiles(path string) ([]PlayerProfile, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []PlayerProfile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	profiles := []PlayerProfile{}
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var profile PlayerProfile
+		if err := json.Unmarshal(scanner.Bytes(), &profile); err != nil {
+			// a torn last line after a crash must not lose all other profiles
+			continue
+		}
+		if i, ok := index[profile.ID]; ok {
+			profiles[i] = profile
+		} else {
+			index[profile.ID] = len(profiles)
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, scanner.Err()
+}
+
+func writeProfiles(path string, profiles []PlayerProfile) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, profile := range profiles {
+		if err := encoder.Encode(profile); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	return errors.Join(writer.Flush(), file.Sync(), file.Close())
+}
@@ -0,0 +1,139 @@
+package rcon
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zMoooooritz/go-let-loose/pkg/hll"
+)
+
+func TestProfileStore(t *testing.T) {
+	start := time.Unix(1700000000, 0).UTC()
+	at := func(minutes int, eventType hll.EventType) hll.GenericEvent {
+		return hll.GenericEvent{EventType: eventType, EventTime: start.Add(time.Duration(minutes) * time.Minute)}
+	}
+	snapshot := func(minutes int, players ...hll.DetailedPlayerInfo) hll.ServerSnapshotEvent {
+		return hll.ServerSnapshotEvent{GenericEvent: at(minutes, hll.EVENT_SERVER_SNAPSHOT), Players: players}
+	}
+
+	alice := hll.PlayerInfo{Name: "Alice", ID: "1"}
+	renamed := hll.PlayerInfo{Name: "Alicia", ID: "1"}
+	bob := hll.PlayerInfo{Name: "Bob", ID: "2"}
+
+	events := []hll.Event{
+		hll.ConnectEvent{GenericEvent: at(0, hll.EVENT_CONNECTED), Player: alice},
+		hll.KillEvent{GenericEvent: at(5, hll.EVENT_KILL), Killer: alice, Victim: bob},
+		hll.DeathEvent{GenericEvent: at(5, hll.EVENT_DEATH), Killer: alice, Victim: bob},
+		// bob was already on the server and is only picked up by the snapshot
+		snapshot(10,
+			hll.DetailedPlayerInfo{PlayerInfo: alice, ClanTag: "ABC", Platform: hll.PLAYER_PLATFORM_STEAM},
+			hll.DetailedPlayerInfo{PlayerInfo: bob, Platform: hll.PLAYER_PLATFORM_EPIC},
+		),
+		hll.DisconnectEvent{GenericEvent: at(30, hll.EVENT_DISCONNECTED), Player: alice},
+		hll.ConnectEvent{GenericEvent: at(60, hll.EVENT_CONNECTED), Player: renamed},
+		hll.PlayerClanTagChangedEvent{GenericEvent: at(61, hll.EVENT_CLAN_TAG_CHANGED), Player: renamed, OldClanTag: "ABC", NewClanTag: ""},
+		// bob left without a disconnect event
+		snapshot(70, hll.DetailedPlayerInfo{PlayerInfo: renamed}),
+		hll.DisconnectEvent{GenericEvent: at(90, hll.EVENT_DISCONNECTED), Player: renamed},
+	}
+
+	path := filepath.Join(t.TempDir(), "profiles.jsonl")
+	backend, err := NewFileProfileBackend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := newProfileStore(backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range events {
+		store.Notify(event)
+	}
+
+	check := func(t *testing.T, store *ProfileStore) {
+		profile, ok := store.Profile("1")
+		if !ok {
+			t.Fatal("Expected a profile of alice")
+		}
+		if profile.Name() != "Alicia" || len(profile.Names) != 2 {
+			t.Errorf("Expected the name history [Alice Alicia], but got %v", profile.Names)
+		}
+		if profile.ClanTag() != "" || len(profile.ClanTags) != 2 {
+			t.Errorf("Expected the removed clan tag to be recorded, but got %v", profile.ClanTags)
+		}
+		if profile.Platform != hll.PLAYER_PLATFORM_STEAM {
+			t.Errorf("Expected platform steam, but got %s", profile.Platform)
+		}
+		if profile.Sessions != 2 || profile.Playtime != 60*time.Minute {
+			t.Errorf("Expected 2 sessions with 60m, but got %d with %s", profile.Sessions, profile.Playtime)
+		}
+		if profile.Kills != 1 || profile.Deaths != 0 {
+			t.Errorf("Expected 1 kill and 0 deaths, but got %d and %d", profile.Kills, profile.Deaths)
+		}
+		if !profile.FirstSeen.Equal(start) || !profile.LastSeen.Equal(start.Add(90*time.Minute)) {
+			t.Errorf("Expected to be seen from %s to %s, but got %s to %s", start, start.Add(90*time.Minute), profile.FirstSeen, profile.LastSeen)
+		}
+
+		profile, ok = store.Profile("2")
+		if !ok {
+			t.Fatal("Expected a profile of bob")
+		}
+		if profile.Sessions != 1 || profile.Playtime != 60*time.Minute {
+			t.Errorf("Expected the reconciled session of 60m, but got %d with %s", profile.Sessions, profile.Playtime)
+		}
+		if profile.Deaths != 1 || profile.Platform != hll.PLAYER_PLATFORM_EPIC {
+			t.Errorf("Expected 1 death on epic, but got %d on %s", profile.Deaths, profile.Platform)
+		}
+	}
+
+	t.Run("profiles", func(t *testing.T) {
+		check(t, store)
+	})
+
+	t.Run("queries", func(t *testing.T) {
+		if name, _ := store.NameAt("1", start.Add(20*time.Minute)); name != "Alice" {
+			t.Errorf("Expected Alice, but got %s", name)
+		}
+		if name, _ := store.NameAt("1", start.Add(80*time.Minute)); name != "Alicia" {
+			t.Errorf("Expected Alicia, but got %s", name)
+		}
+		if _, ok := store.NameAt("1", start.Add(-time.Minute)); ok {
+			t.Error("Expected no name before the player was first seen")
+		}
+		if found := store.FindByName("alice"); len(found) != 1 || found[0].ID != "1" {
+			t.Errorf("Expected to find alice by her old name, but got %v", found)
+		}
+		if found := store.FindByClanTag("abc"); len(found) != 1 || found[0].ID != "1" {
+			t.Errorf("Expected to find alice by her old clan tag, but got %v", found)
+		}
+		if profiles := store.Profiles(); len(profiles) != 2 || profiles[0].ID != "1" {
+			t.Errorf("Expected the most recently seen profile first, but got %v", profiles)
+		}
+	})
+
+	t.Run("persistence", func(t *testing.T) {
+		if err := store.Close(); err != nil {
+			t.Fatal(err)
+		}
+		backend, err := NewFileProfileBackend(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer backend.Close()
+		reopened, err := newProfileStore(backend)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, reopened)
+
+		// the reopened file is compacted to one record per player
+		profiles, err := readProfiles(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if backend.records != len(profiles) || len(profiles) != 2 {
+			t.Errorf("Expected 2 compacted records, but got %d", backend.records)
+		}
+	})
+}
@@ -57,6 +57,7 @@ type Rcon struct {
 	verification *rconVerification
 	worker       *WorkerManager
 	jobChannel   chan rconJob
+	layout       *sectorLayout
 }
 
 type RconOption func(*Rcon)
@@ -79,6 +80,7 @@ func NewRcon(cfg ServerConfig, workerCount int, opts ...RconOption) (*Rcon, erro
 		verification: &rconVerification{},
 		worker:       workerManager,
 		jobChannel:   jobChannel,
+		layout:       &sectorLayout{},
 	}
 
 	for _, opt := range opts {
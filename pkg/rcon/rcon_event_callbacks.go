@@ -111,3 +111,123 @@ func (r *Rcon) OnPositionChanged(callback func(hll.PlayerPositionChangedEvent))
 func (r *Rcon) OnClanTagChanged(callback func(hll.PlayerClanTagChangedEvent)) {
 	r.Events.registerEvent(hll.EVENT_CLAN_TAG_CHANGED, callbackObserver[hll.PlayerClanTagChangedEvent]{callback: callback})
 }
+
+func (r *Rcon) OnPlayerSpawned(callback func(hll.PlayerSpawnedEvent)) {
+	r.Events.registerEvent(hll.EVENT_PLAYER_SPAWNED, callbackObserver[hll.PlayerSpawnedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnPlayerDespawned(callback func(hll.PlayerDespawnedEvent)) {
+	r.Events.registerEvent(hll.EVENT_PLAYER_DESPAWNED, callbackObserver[hll.PlayerDespawnedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnVehicleDestroyed(callback func(hll.PlayerVehicleDestroyedEvent)) {
+	r.Events.registerEvent(hll.EVENT_VEHICLE_DESTROYED, callbackObserver[hll.PlayerVehicleDestroyedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnVehicleKill(callback func(hll.PlayerVehicleKillEvent)) {
+	r.Events.registerEvent(hll.EVENT_VEHICLE_KILL, callbackObserver[hll.PlayerVehicleKillEvent]{callback: callback})
+}
+
+func (r *Rcon) OnLevelUp(callback func(hll.PlayerLevelUpEvent)) {
+	r.Events.registerEvent(hll.EVENT_LEVEL_UP, callbackObserver[hll.PlayerLevelUpEvent]{callback: callback})
+}
+
+func (r *Rcon) OnKillStreak(callback func(hll.KillStreakEvent)) {
+	r.Events.registerEvent(hll.EVENT_KILL_STREAK, callbackObserver[hll.KillStreakEvent]{callback: callback})
+}
+
+func (r *Rcon) OnKillStreakEnded(callback func(hll.KillStreakEndedEvent)) {
+	r.Events.registerEvent(hll.EVENT_KILL_STREAK_ENDED, callbackObserver[hll.KillStreakEndedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnMultiKill(callback func(hll.MultiKillEvent)) {
+	r.Events.registerEvent(hll.EVENT_MULTI_KILL, callbackObserver[hll.MultiKillEvent]{callback: callback})
+}
+
+func (r *Rcon) OnMatchPhaseChanged(callback func(hll.MatchPhaseChangedEvent)) {
+	r.Events.registerEvent(hll.EVENT_MATCH_PHASE_CHANGED, callbackObserver[hll.MatchPhaseChangedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnSectorCaptured(callback func(hll.SectorCapturedEvent)) {
+	r.Events.registerEvent(hll.EVENT_SECTOR_CAPTURED, callbackObserver[hll.SectorCapturedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnServerFull(callback func(hll.ServerFullEvent)) {
+	r.Events.registerEvent(hll.EVENT_SERVER_FULL, callbackObserver[hll.ServerFullEvent]{callback: callback})
+}
+
+func (r *Rcon) OnServerNotFull(callback func(hll.ServerNotFullEvent)) {
+	r.Events.registerEvent(hll.EVENT_SERVER_NOT_FULL, callbackObserver[hll.ServerNotFullEvent]{callback: callback})
+}
+
+func (r *Rcon) OnQueueChanged(callback func(hll.QueueChangedEvent)) {
+	r.Events.registerEvent(hll.EVENT_QUEUE_CHANGED, callbackObserver[hll.QueueChangedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnVIPQueueChanged(callback func(hll.VIPQueueChangedEvent)) {
+	r.Events.registerEvent(hll.EVENT_VIP_QUEUE_CHANGED, callbackObserver[hll.VIPQueueChangedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnPopulationThresholdCrossed(callback func(hll.PopulationThresholdCrossedEvent)) {
+	r.Events.registerEvent(hll.EVENT_POPULATION_CROSSED, callbackObserver[hll.PopulationThresholdCrossedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnSquadCreated(callback func(hll.SquadCreatedEvent)) {
+	r.Events.registerEvent(hll.EVENT_SQUAD_CREATED, callbackObserver[hll.SquadCreatedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnSquadDisbanded(callback func(hll.SquadDisbandedEvent)) {
+	r.Events.registerEvent(hll.EVENT_SQUAD_DISBANDED, callbackObserver[hll.SquadDisbandedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnSquadLeaderChanged(callback func(hll.SquadLeaderChangedEvent)) {
+	r.Events.registerEvent(hll.EVENT_SQUAD_LEADER_CHANGED, callbackObserver[hll.SquadLeaderChangedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnSquadLeaderless(callback func(hll.SquadLeaderlessEvent)) {
+	r.Events.registerEvent(hll.EVENT_SQUAD_LEADERLESS, callbackObserver[hll.SquadLeaderlessEvent]{callback: callback})
+}
+
+func (r *Rcon) OnCommanderTookRole(callback func(hll.CommanderTookRoleEvent)) {
+	r.Events.registerEvent(hll.EVENT_COMMANDER_TOOK_ROLE, callbackObserver[hll.CommanderTookRoleEvent]{callback: callback})
+}
+
+func (r *Rcon) OnCommanderLeftRole(callback func(hll.CommanderLeftRoleEvent)) {
+	r.Events.registerEvent(hll.EVENT_COMMANDER_LEFT_ROLE, callbackObserver[hll.CommanderLeftRoleEvent]{callback: callback})
+}
+
+func (r *Rcon) OnServerSnapshot(callback func(hll.ServerSnapshotEvent)) {
+	r.Events.registerEvent(hll.EVENT_SERVER_SNAPSHOT, callbackObserver[hll.ServerSnapshotEvent]{callback: callback})
+}
+
+func (r *Rcon) OnSessionClosed(callback func(hll.SessionClosedEvent)) {
+	r.Events.registerEvent(hll.EVENT_SESSION_CLOSED, callbackObserver[hll.SessionClosedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnStrongpointEntered(callback func(hll.StrongpointEnteredEvent)) {
+	r.Events.registerEvent(hll.EVENT_STRONGPOINT_ENTERED, callbackObserver[hll.StrongpointEnteredEvent]{callback: callback})
+}
+
+func (r *Rcon) OnStrongpointLeft(callback func(hll.StrongpointLeftEvent)) {
+	r.Events.registerEvent(hll.EVENT_STRONGPOINT_LEFT, callbackObserver[hll.StrongpointLeftEvent]{callback: callback})
+}
+
+func (r *Rcon) OnEnemyHQEntered(callback func(hll.EnemyHQEnteredEvent)) {
+	r.Events.registerEvent(hll.EVENT_ENEMY_HQ_ENTERED, callbackObserver[hll.EnemyHQEnteredEvent]{callback: callback})
+}
+
+func (r *Rcon) OnEnemyProximity(callback func(hll.EnemyProximityEvent)) {
+	r.Events.registerEvent(hll.EVENT_ENEMY_PROXIMITY, callbackObserver[hll.EnemyProximityEvent]{callback: callback})
+}
+
+func (r *Rcon) OnCrewFormed(callback func(hll.CrewFormedEvent)) {
+	r.Events.registerEvent(hll.EVENT_CREW_FORMED, callbackObserver[hll.CrewFormedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnCrewDissolved(callback func(hll.CrewDissolvedEvent)) {
+	r.Events.registerEvent(hll.EVENT_CREW_DISSOLVED, callbackObserver[hll.CrewDissolvedEvent]{callback: callback})
+}
+
+func (r *Rcon) OnSuspiciousKill(callback func(hll.SuspiciousKillEvent)) {
+	r.Events.registerEvent(hll.EVENT_SUSPICIOUS_KILL, callbackObserver[hll.SuspiciousKillEvent]{callback: callback})
+}
@@ -118,9 +118,10 @@ func killToDeatchEvent(killEvent hll.KillEvent) hll.DeathEvent {
 			EventType: hll.EVENT_DEATH,
 			EventTime: killEvent.EventTime,
 		},
-		Victim: killEvent.Victim,
-		Killer: killEvent.Killer,
-		Weapon: killEvent.Weapon,
+		Victim:  killEvent.Victim,
+		Killer:  killEvent.Killer,
+		Weapon:  killEvent.Weapon,
+		Context: killEvent.Context,
 	}
 }
 
@@ -155,9 +156,10 @@ func teamKillToTeamDeathEvent(teamKillEvent hll.TeamKillEvent) hll.TeamDeathEven
 			EventType: hll.EVENT_TEAMDEATH,
 			EventTime: teamKillEvent.EventTime,
 		},
-		Victim: teamKillEvent.Victim,
-		Killer: teamKillEvent.Killer,
-		Weapon: teamKillEvent.Weapon,
+		Victim:  teamKillEvent.Victim,
+		Killer:  teamKillEvent.Killer,
+		Weapon:  teamKillEvent.Weapon,
+		Context: teamKillEvent.Context,
 	}
 }
 
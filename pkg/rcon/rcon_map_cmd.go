@@ -3,12 +3,47 @@ package rcon
 import (
 	"errors"
 	"math/rand"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/zMoooooritz/go-let-loose/internal/socket/api"
 	"github.com/zMoooooritz/go-let-loose/pkg/hll"
 )
 
+// the sector layout set via SetGameLayout, since the server does not report the active strongpoints
+type sectorLayout struct {
+	layer      hll.LayerIdentifier
+	objectives []string
+	mutex      sync.Mutex
+}
+
+func (sl *sectorLayout) set(layer hll.LayerIdentifier, objectives []string) {
+	sl.mutex.Lock()
+	defer sl.mutex.Unlock()
+	sl.layer = layer
+	sl.objectives = slices.Clone(objectives)
+}
+
+func (sl *sectorLayout) get(layer hll.LayerIdentifier) []string {
+	sl.mutex.Lock()
+	defer sl.mutex.Unlock()
+	if sl.layer != layer {
+		return []string{}
+	}
+	return slices.Clone(sl.objectives)
+}
+
+// the objectives of an ended match must not be carried over into the next match on the same layer
+func (sl *sectorLayout) processMatch(event hll.Event) {
+	if e, ok := event.(hll.MatchPhaseChangedEvent); ok && e.NewPhase == hll.MATCH_PHASE_ENDED {
+		sl.mutex.Lock()
+		defer sl.mutex.Unlock()
+		sl.layer = ""
+		sl.objectives = []string{}
+	}
+}
+
 func (r *Rcon) GetCurrentMap() (hll.Map, error) {
 	resp, err := getSessionInfo(r)
 	if err != nil {
@@ -212,7 +247,13 @@ func (r *Rcon) SetGameLayout(objs []string) error {
 			SectorFive:  objs[4],
 		},
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if layer, err := r.GetCurrentLayer(); err == nil {
+		r.layout.set(layer.ID, objs)
+	}
+	return nil
 }
 
 func (r *Rcon) SetDynamicWeatherToggle(layer hll.Layer, enabled bool) error {
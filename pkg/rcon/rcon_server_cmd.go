@@ -19,23 +19,11 @@ func (r *Rcon) GetSlots() (int, int, error) {
 }
 
 func (r *Rcon) GetGameState() (hll.GameState, error) {
-	resp, err := getSessionInfo(r)
+	sessionInfo, err := r.GetSessionInfo()
 	if err != nil {
 		return hll.GameState{}, err
 	}
-	return hll.GameState{
-		PlayerCount: hll.TeamData{
-			Allies: int(resp.AlliedPlayerCount),
-			Axis:   int(resp.AxisPlayerCount),
-		},
-		GameScore: hll.TeamData{
-			Allies: int(resp.AlliedScore),
-			Axis:   int(resp.AxisScore),
-		},
-		RemainingSeconds: int(resp.RemainingMatchTime),
-		CurrentMap:       hll.Layer{},
-		NextMap:          hll.Layer{},
-	}, nil
+	return toGameState(sessionInfo), nil
 }
 
 func (r *Rcon) GetPlayerCounts() (hll.TeamData, error) {
@@ -143,6 +131,7 @@ func (r *Rcon) GetSessionInfo() (hll.SessionInfo, error) {
 	return hll.SessionInfo{
 		ServerName:         resp.ServerName,
 		MapName:            resp.MapName,
+		MapID:              resp.MapID,
 		GameMode:           hll.GameModeIdentifier(resp.GameMode),
 		RemainingMatchTime: time.Second * time.Duration(resp.RemainingMatchTime),
 		MatchTime:          time.Second * time.Duration(resp.MatchTime),
@@ -213,3 +202,20 @@ func (r *Rcon) GetServerChangelist() (string, error) {
 	}
 	return resp.Changelist, nil
 }
+
+func toGameState(sessionInfo hll.SessionInfo) hll.GameState {
+	currentLayer, _ := hll.ParseLayer(sessionInfo.MapID)
+	return hll.GameState{
+		PlayerCount: hll.TeamData{
+			Allies: sessionInfo.AlliedPlayerCount,
+			Axis:   sessionInfo.AxisPlayerCount,
+		},
+		GameScore: hll.TeamData{
+			Allies: sessionInfo.AlliedScore,
+			Axis:   sessionInfo.AxisScore,
+		},
+		RemainingSeconds: int(sessionInfo.RemainingMatchTime.Seconds()),
+		CurrentMap:       currentLayer,
+		NextMap:          hll.Layer{},
+	}
+}
@@ -260,6 +260,45 @@ function getSessionInfo() end
 ---@return GameState|nil state Game state if successful
 function getGameState() end
 
+---Get the current match as tracked by the event system
+---@return string|nil error Error message if any
+---@return Match|nil match The current match if successful
+function currentMatch() end
+
+---Get the sessions of all players currently on the server as tracked by the event system
+---@return string|nil error Error message if any
+---@return PlayerSession[]|nil sessions The running sessions if successful
+function currentSessions() end
+
+---Get the players inside the active strongpoints of the current layer as tracked by the event system
+---@return string|nil error Error message if any
+---@return StrongpointPresence[]|nil presence The presence per strongpoint if successful
+function strongpointPresence() end
+
+---Get the other spawned players near a player as of the latest poll, nearest first
+---@param playerId string The ID of the player
+---@param radius number The planar radius in cm
+---@return string|nil error Error message if any
+---@return DetailedPlayerInfo[]|nil players The players within the radius if successful
+function playersNear(playerId, radius) end
+
+---Get the spawned enemies nearest to a player as of the latest poll, nearest first
+---@param playerId string The ID of the player
+---@param count integer The maximal amount of enemies
+---@return string|nil error Error message if any
+---@return DetailedPlayerInfo[]|nil enemies The nearest enemies if successful
+function nearestEnemies(playerId, count) end
+
+---Get the probable vehicle crews as inferred from co-located players by the event system
+---@return string|nil error Error message if any
+---@return VehicleCrew[]|nil crews The crews of the latest poll if successful
+function vehicleCrews() end
+
+---Get the kill distances per weapon type of the current match as tracked by the event system
+---@return string|nil error Error message if any
+---@return table<string, KillDistanceStats>|nil distances The distances per weapon type if successful
+function killDistances() end
+
 ---Get player slots (current, max)
 ---@return string|nil error Error message if any
 ---@return number|nil current Current player count if successful
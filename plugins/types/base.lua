@@ -61,6 +61,22 @@ local Score = {}
 ---@field Z number Z coordinate (elevation)
 local Position = {}
 
+---Strongpoint of a sector
+---@class Strongpoint
+---@field ID string Strongpoint identifier (e.g., "BLACTOT")
+---@field Name string Human-readable strongpoint name (e.g., "Blactot")
+---@field Center Position The center of the strongpoint
+---@field Radius number The radius of the strongpoint
+local Strongpoint = {}
+
+---The players of each team inside a strongpoint
+---@class StrongpointPresence
+---@field Strongpoint Strongpoint The strongpoint
+---@field SectorIndex integer The index of the sector, ordered left-to-right or top-to-bottom (0-based)
+---@field Allies PlayerInfo[] The allied players inside the strongpoint
+---@field Axis PlayerInfo[] The axis players inside the strongpoint
+local StrongpointPresence = {}
+
 ---Team score data
 ---@class TeamData
 ---@field Allies number Allied team score/count
@@ -98,6 +114,72 @@ local Layer = {}
 ---@field NextMap Layer Next map/layer
 local GameState = {}
 
+---Current match and its lifecycle phase
+---@class Match
+---@field Layer Layer The layer the match is played on
+---@field Phase string The match phase ("Unknown"/"Warmup"/"In Progress"/"Ended")
+---@field StartTime string Timestamp when the match started (estimated if joined mid-match)
+---@field EndTime string Timestamp when the match ended
+---@field Score TeamData The final score, once the match ended
+local Match = {}
+
+---A player session between joining and leaving the server
+---@class PlayerSession
+---@field Player PlayerInfo The player
+---@field Start string Timestamp when the player joined
+---@field End string Timestamp when the player left, zero while the session is running
+---@field Inferred boolean Whether the disconnect was missed and the end is the last time the player was seen
+---@field TeamTime table<string, integer> The time spent per team in nanoseconds
+---@field RoleTime table<string, integer> The time spent per role in nanoseconds
+local PlayerSession = {}
+
+---Vehicle seat information
+---@class VehicleSeat
+---@field Index integer The index of the seat
+---@field Type string The seat type ("Driver", "Loader", "Gunner", "Spotter", "Passenger")
+---@field Weapons string[] The weapons operated from this seat
+---@field RequiresRoles string[] The roles allowed to take this seat, any role if empty
+---@field Exposed boolean Whether the occupant is exposed
+local VehicleSeat = {}
+
+---Vehicle information
+---@class Vehicle
+---@field ID string Vehicle identifier
+---@field Name string Human-readable vehicle name
+---@field Factions string[] The factions using this vehicle
+---@field Type string The vehicle type (e.g., "Medium Tank")
+---@field Seats VehicleSeat[] The seats of the vehicle
+local Vehicle = {}
+
+---A group of co-located players of one team that probably share a vehicle
+---@class VehicleCrew
+---@field ID integer Unique while the process runs
+---@field Team string The team of the crew
+---@field Faction string The faction of the crew
+---@field Members DetailedPlayerInfo[] The armor crewmen and vehicle weapon killers as of the latest poll, passengers are not counted
+---@field Candidates Vehicle[] The vehicles the crew probably uses, best match first
+---@field Weapons string[] The vehicle weapons the members recently killed with
+---@field Position Position The center of the crew as of the latest poll
+---@field Origin Position The center of the crew when it formed
+---@field MaxDistance integer The farthest planar distance in cm the crew got from its origin
+---@field Formed string Timestamp when the crew formed
+---@field LastSeen string Timestamp of the latest poll the crew was seen in
+local VehicleCrew = {}
+
+---The distances of the kills with one weapon type
+---@class KillDistanceStats
+---@field Kills integer The amount of kills with a known distance
+---@field Total number The summed up distance in meters
+---@field Max number The longest distance in meters
+local KillDistanceStats = {}
+
+---A single piece of evidence of a suspicious kill
+---@class SuspicionEvidence
+---@field Kind string The kind of evidence ("Long Range", "Foreign Weapon", "Kill Rate")
+---@field Score number The score added by this evidence
+---@field Detail string A human-readable explanation
+local SuspicionEvidence = {}
+
 ---Admin information
 ---@class Admin
 ---@field UserId string Admin's player ID
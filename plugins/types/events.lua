@@ -4,13 +4,25 @@
 ---@class BaseEvent
 ---@field EventType string The type of event
 ---@field EventTime string ISO timestamp when the event occurred
+---@field EventSequence integer Monotonic number assigned when the event is delivered
+---@field EventSource string Origin of the event ("Log" or "Poll")
 local BaseEvent = {}
 
+---Kill context - state of both players at the time of a kill
+---@class KillContext
+---@field Killer DetailedPlayerInfo The killer as seen in the latest server info snapshot
+---@field Victim DetailedPlayerInfo The victim as seen in the latest server info snapshot
+---@field Distance number The kill distance in meters (0 if unknown)
+---@field KillerPositionLive boolean False if the position of the killer is the last known one of an earlier snapshot
+---@field VictimPositionLive boolean False if the position of the victim is the last known one of an earlier snapshot
+local KillContext = {}
+
 ---Kill event - fired when a player kills another player
 ---@class KillEvent : BaseEvent
 ---@field Killer PlayerInfo The player who made the kill
 ---@field Victim PlayerInfo The player who was killed
 ---@field Weapon Weapon The weapon used for the kill
+---@field Context KillContext|nil State of both players at kill time, if known
 local KillEvent = {}
 
 ---Death event - fired when a player dies
@@ -18,6 +30,7 @@ local KillEvent = {}
 ---@field Victim PlayerInfo The player who died
 ---@field Killer PlayerInfo The player who caused the death
 ---@field Weapon Weapon The weapon that caused the death
+---@field Context KillContext|nil State of both players at kill time, if known
 local DeathEvent = {}
 
 ---Team kill event - fired when a player team kills
@@ -25,6 +38,7 @@ local DeathEvent = {}
 ---@field Killer PlayerInfo The player who made the team kill
 ---@field Victim PlayerInfo The teammate who was killed
 ---@field Weapon Weapon The weapon used for the team kill
+---@field Context KillContext|nil State of both players at kill time, if known
 local TeamKillEvent = {}
 
 ---Team death event - fired when a player dies to a teammate
@@ -32,6 +46,7 @@ local TeamKillEvent = {}
 ---@field Victim PlayerInfo The player who died to a teammate
 ---@field Killer PlayerInfo The teammate who caused the death
 ---@field Weapon Weapon The weapon that caused the team death
+---@field Context KillContext|nil State of both players at kill time, if known
 local TeamDeathEvent = {}
 
 ---Chat event - fired when a player sends a chat message
@@ -168,6 +183,206 @@ local PositionEvent = {}
 ---@field NewClanTag string The new clan tag
 local ClanTagChangeEvent = {}
 
+---Player spawned event - fired when a player (re)spawns on the map
+---@class PlayerSpawnedEvent : BaseEvent
+---@field Player PlayerInfo The player who spawned
+---@field Position Position The spawn location
+---@field GridReference string The grid reference of the spawn location as shown on the in-game map, e.g. "E5-3"
+---@field Strongpoint Strongpoint|nil The nearest strongpoint of the current layer
+local PlayerSpawnedEvent = {}
+
+---Player despawned event - fired when a player leaves the map by dying or redeploying
+---@class PlayerDespawnedEvent : BaseEvent
+---@field Player PlayerInfo The player who despawned
+---@field Position Position The last known position before the despawn
+---@field Reason string The reason of the despawn ("Death"/"Redeploy")
+---@field Killer PlayerInfo The killer, if the despawn could be tied to a logged death
+local PlayerDespawnedEvent = {}
+
+---Vehicle destroyed event - fired when a player destroyed a vehicle
+---@class VehicleDestroyedEvent : BaseEvent
+---@field Player PlayerInfo The player who destroyed the vehicle
+---@field OldCount integer The previous number of destroyed vehicles
+---@field NewCount integer The new number of destroyed vehicles
+local VehicleDestroyedEvent = {}
+
+---Vehicle kill event - fired when a player got a vehicle kill
+---@class VehicleKillEvent : BaseEvent
+---@field Player PlayerInfo The player who got the vehicle kill
+---@field OldCount integer The previous number of vehicle kills
+---@field NewCount integer The new number of vehicle kills
+local VehicleKillEvent = {}
+
+---Level up event - fired when a player reaches a new level
+---@class LevelUpEvent : BaseEvent
+---@field Player PlayerInfo The player who leveled up
+---@field OldLevel integer The previous level
+---@field NewLevel integer The new level
+local LevelUpEvent = {}
+
+---Kill streak event - fired when a kill streak reaches a configured threshold
+---@class KillStreakEvent : BaseEvent
+---@field Player PlayerInfo The player on the kill streak
+---@field Streak integer The number of kills without dying
+local KillStreakEvent = {}
+
+---Kill streak ended event - fired when a player on a kill streak dies
+---@class KillStreakEndedEvent : BaseEvent
+---@field Player PlayerInfo The player whose streak ended
+---@field Streak integer The number of kills of the ended streak
+---@field EndedBy PlayerInfo The player who ended the streak
+local KillStreakEndedEvent = {}
+
+---Multi kill event - fired when a player gets multiple kills within a short time window
+---@class MultiKillEvent : BaseEvent
+---@field Player PlayerInfo The player who got the kills
+---@field Kills integer The number of kills within the window
+---@field Window integer The time between the first and the last kill in nanoseconds
+---@field Victims PlayerInfo[] The players who got killed
+local MultiKillEvent = {}
+
+---Match phase changed event - fired when the match lifecycle phase changes
+---@class MatchPhaseChangedEvent : BaseEvent
+---@field Match Match The current match
+---@field OldPhase string The previous phase
+---@field NewPhase string The new phase
+local MatchPhaseChangedEvent = {}
+
+---Sector captured event - fired when a sector changes hands
+---@class SectorCapturedEvent : BaseEvent
+---@field Team string The team that captured the sector
+---@field SectorIndex integer The index of the sector, ordered left-to-right or top-to-bottom (0-based)
+---@field Sector table The captured sector with its capture zones
+---@field Strongpoint Strongpoint|nil The captured strongpoint, if known
+---@field OldScore TeamData The previous score
+---@field NewScore TeamData The new score
+local SectorCapturedEvent = {}
+
+---@class ServerFullEvent : BaseEvent
+---@field PlayerCount integer The current number of players
+---@field MaxPlayerCount integer The maximum number of players
+---@field QueueCount integer The current length of the queue
+local ServerFullEvent = {}
+
+---@class ServerNotFullEvent : BaseEvent
+---@field PlayerCount integer The current number of players
+---@field MaxPlayerCount integer The maximum number of players
+local ServerNotFullEvent = {}
+
+---@class QueueChangedEvent : BaseEvent
+---@field OldCount integer The previous length of the queue
+---@field NewCount integer The new length of the queue
+---@field MaxCount integer The maximum length of the queue
+local QueueChangedEvent = {}
+
+---@class VIPQueueChangedEvent : BaseEvent
+---@field OldCount integer The previous length of the VIP queue
+---@field NewCount integer The new length of the VIP queue
+---@field MaxCount integer The maximum length of the VIP queue
+local VIPQueueChangedEvent = {}
+
+---@class PopulationThresholdCrossedEvent : BaseEvent
+---@field Threshold integer The crossed player count threshold
+---@field OldCount integer The previous number of players
+---@field NewCount integer The new number of players
+---@field Rising boolean Whether the population grew past the threshold
+local PopulationThresholdCrossedEvent = {}
+
+---@class SquadCreatedEvent : BaseEvent
+---@field Squad SquadView The created squad
+local SquadCreatedEvent = {}
+
+---@class SquadDisbandedEvent : BaseEvent
+---@field Squad SquadView The last known state of the disbanded squad
+local SquadDisbandedEvent = {}
+
+---@class SquadLeaderChangedEvent : BaseEvent
+---@field Squad SquadView The squad
+---@field OldLeader PlayerInfo The previous squad leader, empty if there was none
+---@field NewLeader PlayerInfo The new squad leader
+local SquadLeaderChangedEvent = {}
+
+---@class SquadLeaderlessEvent : BaseEvent
+---@field Squad SquadView The squad without a leader
+---@field OldLeader PlayerInfo The previous squad leader
+local SquadLeaderlessEvent = {}
+
+---@class CommanderTookRoleEvent : BaseEvent
+---@field Team string The team of the commander
+---@field Player PlayerInfo The new commander
+local CommanderTookRoleEvent = {}
+
+---@class CommanderLeftRoleEvent : BaseEvent
+---@field Team string The team of the commander
+---@field Player PlayerInfo The previous commander
+local CommanderLeftRoleEvent = {}
+
+---@class ServerSnapshotEvent : BaseEvent
+---@field SessionInfo SessionInfo The polled session information
+---@field Players DetailedPlayerInfo[] All players on the server
+local ServerSnapshotEvent = {}
+
+---Session closed event - fired when a player left the server
+---@class SessionClosedEvent : BaseEvent
+---@field Session PlayerSession The closed session
+local SessionClosedEvent = {}
+
+---Strongpoint entered event - fired when a spawned player moves into an active strongpoint
+---@class StrongpointEnteredEvent : BaseEvent
+---@field Player PlayerInfo The player who entered the strongpoint
+---@field Team string The team of the player
+---@field Strongpoint Strongpoint The strongpoint
+---@field SectorIndex integer The index of the sector, ordered left-to-right or top-to-bottom (0-based)
+---@field Counts TeamData The players of each team inside the strongpoint after entering
+local StrongpointEnteredEvent = {}
+
+---Strongpoint left event - fired when a player leaves, dies in or disconnects from an active strongpoint, or the layout turns out not to include it
+---@class StrongpointLeftEvent : BaseEvent
+---@field Player PlayerInfo The player who left the strongpoint
+---@field Team string The team of the player
+---@field Strongpoint Strongpoint The strongpoint
+---@field SectorIndex integer The index of the sector, ordered left-to-right or top-to-bottom (0-based)
+---@field Counts TeamData The players of each team inside the strongpoint after leaving
+local StrongpointLeftEvent = {}
+
+---Enemy HQ entered event - fired when a spawned player moves into the home sector of the enemy team, e.g. to camp its spawns
+---@class EnemyHQEnteredEvent : BaseEvent
+---@field Player PlayerInfo The player who entered the enemy home sector
+---@field Team string The team of the player
+---@field SectorIndex integer The index of the sector, ordered left-to-right or top-to-bottom (0-based)
+---@field Position Position The position of the player
+---@field Count integer The players of the team inside the enemy home sector after entering
+local EnemyHQEnteredEvent = {}
+
+---Enemy proximity event - fired once an enemy comes within the radius of a watched player, e.g. the commander
+---@class EnemyProximityEvent : BaseEvent
+---@field Rule string The name of the proximity rule that matched
+---@field Player PlayerInfo The watched player
+---@field Role string The role of the watched player
+---@field Enemy PlayerInfo The enemy who came close
+---@field Distance integer The planar distance in cm
+local EnemyProximityEvent = {}
+
+---Crew formed event - fired once co-located players probably started to share a vehicle
+---@class CrewFormedEvent : BaseEvent
+---@field Crew VehicleCrew The crew
+local CrewFormedEvent = {}
+
+---Crew dissolved event - fired once the members of a crew no longer share a vehicle
+---@class CrewDissolvedEvent : BaseEvent
+---@field Crew VehicleCrew The crew as of the last poll it was seen in
+local CrewDissolvedEvent = {}
+
+---Suspicious kill event - fired when the evidence against a kill reaches the configured score, a hint and no proof
+---@class SuspiciousKillEvent : BaseEvent
+---@field Killer PlayerInfo The player who killed
+---@field Victim PlayerInfo The player who was killed
+---@field Weapon Weapon The weapon used
+---@field Distance number The kill distance in meters, 0 if unknown
+---@field Score number The summed up score of the evidence
+---@field Evidence SuspicionEvidence[] The evidence against the kill
+local SuspiciousKillEvent = {}
+
 ---Register a kill event handler
 ---@param callback fun(event: KillEvent): nil
 function onKill(callback) end
@@ -266,4 +481,124 @@ function onPositionChanged(callback) end
 
 ---Register a clan tag change event handler
 ---@param callback fun(event: ClanTagChangeEvent): nil
-function onClanTagChanged(callback) end
\ No newline at end of file
+function onClanTagChanged(callback) end
+
+---Register a player spawned event handler
+---@param callback fun(event: PlayerSpawnedEvent): nil
+function onPlayerSpawned(callback) end
+
+---Register a player despawned event handler
+---@param callback fun(event: PlayerDespawnedEvent): nil
+function onPlayerDespawned(callback) end
+
+---Register a vehicle destroyed event handler
+---@param callback fun(event: VehicleDestroyedEvent): nil
+function onVehicleDestroyed(callback) end
+
+---Register a vehicle kill event handler
+---@param callback fun(event: VehicleKillEvent): nil
+function onVehicleKill(callback) end
+
+---Register a level up event handler
+---@param callback fun(event: LevelUpEvent): nil
+function onLevelUp(callback) end
+
+---Register a kill streak event handler
+---@param callback fun(event: KillStreakEvent): nil
+function onKillStreak(callback) end
+
+---Register a kill streak ended event handler
+---@param callback fun(event: KillStreakEndedEvent): nil
+function onKillStreakEnded(callback) end
+
+---Register a multi kill event handler
+---@param callback fun(event: MultiKillEvent): nil
+function onMultiKill(callback) end
+
+---Register a match phase changed event handler
+---@param callback fun(event: MatchPhaseChangedEvent): nil
+function onMatchPhaseChanged(callback) end
+
+---Register a sector captured event handler
+---@param callback fun(event: SectorCapturedEvent): nil
+function onSectorCaptured(callback) end
+
+---Register a server full event handler
+---@param callback fun(event: ServerFullEvent): nil
+function onServerFull(callback) end
+
+---Register a server no longer full event handler
+---@param callback fun(event: ServerNotFullEvent): nil
+function onServerNotFull(callback) end
+
+---Register a queue changed event handler
+---@param callback fun(event: QueueChangedEvent): nil
+function onQueueChanged(callback) end
+
+---Register a VIP queue changed event handler
+---@param callback fun(event: VIPQueueChangedEvent): nil
+function onVIPQueueChanged(callback) end
+
+---Register a population threshold crossed event handler
+---@param callback fun(event: PopulationThresholdCrossedEvent): nil
+function onPopulationThresholdCrossed(callback) end
+
+---Register a squad created event handler
+---@param callback fun(event: SquadCreatedEvent): nil
+function onSquadCreated(callback) end
+
+---Register a squad disbanded event handler
+---@param callback fun(event: SquadDisbandedEvent): nil
+function onSquadDisbanded(callback) end
+
+---Register a squad leader changed event handler
+---@param callback fun(event: SquadLeaderChangedEvent): nil
+function onSquadLeaderChanged(callback) end
+
+---Register a squad leaderless event handler
+---@param callback fun(event: SquadLeaderlessEvent): nil
+function onSquadLeaderless(callback) end
+
+---Register a commander took role event handler
+---@param callback fun(event: CommanderTookRoleEvent): nil
+function onCommanderTookRole(callback) end
+
+---Register a commander left role event handler
+---@param callback fun(event: CommanderLeftRoleEvent): nil
+function onCommanderLeftRole(callback) end
+
+---Register a server snapshot event handler
+---@param callback fun(event: ServerSnapshotEvent): nil
+function onServerSnapshot(callback) end
+
+---Register a session closed event handler
+---@param callback fun(event: SessionClosedEvent): nil
+function onSessionClosed(callback) end
+
+---Register a strongpoint entered event handler
+---@param callback fun(event: StrongpointEnteredEvent): nil
+function onStrongpointEntered(callback) end
+
+---Register a strongpoint left event handler
+---@param callback fun(event: StrongpointLeftEvent): nil
+function onStrongpointLeft(callback) end
+
+---Register an enemy HQ entered event handler
+---@param callback fun(event: EnemyHQEnteredEvent): nil
+function onEnemyHQEntered(callback) end
+
+---Register an enemy proximity event handler
+---@param callback fun(event: EnemyProximityEvent): nil
+function onEnemyProximity(callback) end
+
+---Register a crew formed event handler
+---@param callback fun(event: CrewFormedEvent): nil
+function onCrewFormed(callback) end
+
+---Register a crew dissolved event handler
+---@param callback fun(event: CrewDissolvedEvent): nil
+function onCrewDissolved(callback) end
+
+---Register a suspicious kill event handler
+---@param callback fun(event: SuspiciousKillEvent): nil
+function onSuspiciousKill(callback) end